@@ -0,0 +1,147 @@
+package main
+
+import (
+	"mint/shared/middleware"
+	"mint/storage"
+	"mint/utils/logger"
+	"mint/utils/maintenance"
+	"mint/utils/msg"
+	"mint/utils/mysql"
+	"mint/utils/screening"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueueActionBody defines the request payload of the admin queue operations.
+type QueueActionBody struct {
+	Transaction string `json:"transaction" binding:"required"` // The transaction identifier of the queued payout
+}
+
+// QueuePriorityBody defines the request payload for changing the priority of a queued payout.
+type QueuePriorityBody struct {
+	Transaction string `json:"transaction" binding:"required"` // The transaction identifier of the queued payout
+	Priority    *int   `json:"priority" binding:"required"`    // The new priority; higher values are sent first
+}
+
+// handlerQueueCancel cancels a queued payout that has not been claimed by the worker yet.
+func handlerQueueCancel(ctx *gin.Context) {
+	queueAction(ctx, storage.QUEUE_CANCEL)
+}
+
+// handlerQueueHold puts a queued payout on hold so the worker skips it.
+func handlerQueueHold(ctx *gin.Context) {
+	queueAction(ctx, storage.QUEUE_HOLD)
+}
+
+// handlerQueueRelease returns a held payout back to the queue.
+func handlerQueueRelease(ctx *gin.Context) {
+	queueAction(ctx, storage.QUEUE_RELEASE)
+}
+
+// handlerQueuePriority changes the priority of a queued payout.
+func handlerQueuePriority(ctx *gin.Context) {
+	var body QueuePriorityBody
+
+	// Bind the incoming JSON to QueuePriorityBody and validate the input according to the struct tags
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
+		return
+	}
+
+	result, err := storage.QUEUE_PRIORITY_CONTEXT(ctx.Request.Context(), body.Transaction, *body.Priority, middleware.Actor(ctx))
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	msg.Send(ctx, map[string]any{
+		"result": result,
+	})
+}
+
+// handlerAuditList returns the audit trail of a single transaction.
+func handlerAuditList(ctx *gin.Context) {
+	transaction := ctx.Query("transaction")
+	if len(transaction) == 0 {
+		msg.InvalidFields(ctx, msg.FieldError{Field: "transaction", Rule: "required"})
+		return
+	}
+
+	result, err := storage.AUDIT_GET_CONTEXT(ctx.Request.Context(), transaction)
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	msg.Send(ctx, map[string]any{
+		"result": result,
+	})
+}
+
+// handlerScreeningReload reloads the screening lists from files and the database.
+func handlerScreeningReload(ctx *gin.Context) {
+	if err := screening.Default.Reload(ctx.Request.Context()); err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	msg.Send(ctx, map[string]any{
+		"result": true,
+	})
+}
+
+// queueAction binds a QueueActionBody and applies the given storage operation on behalf of the caller.
+func queueAction(ctx *gin.Context, action func(transaction, actor string) (*bool, *mysql.MySQLError)) {
+	var body QueueActionBody
+
+	// Bind the incoming JSON to QueueActionBody and validate the input according to the struct tags
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
+		return
+	}
+
+	result, err := action(body.Transaction, middleware.Actor(ctx))
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	msg.Send(ctx, map[string]any{
+		"result": result,
+	})
+}
+
+// MaintenanceBody defines the request payload for changing the maintenance mode.
+type MaintenanceBody struct {
+	Mode   string `json:"mode" binding:"required,oneof=off drain pause stop"` // The new maintenance mode
+	Reason string `json:"reason"`                                             // An optional reason shown in the status
+}
+
+// handlerMaintenanceGet returns the maintenance mode in effect.
+func handlerMaintenanceGet(ctx *gin.Context) {
+	msg.Send(ctx, maintenance.Get())
+}
+
+// handlerMaintenanceSet persists and applies a new maintenance mode on behalf of the caller.
+func handlerMaintenanceSet(ctx *gin.Context) {
+	var body MaintenanceBody
+
+	// Bind the incoming JSON to MaintenanceBody and validate the input according to the struct tags
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
+		return
+	}
+
+	if err := maintenance.Set(ctx.Request.Context(), body.Mode, body.Reason, middleware.Actor(ctx)); err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	logger.FromContext(ctx.Request.Context()).Warn("maintenance mode changed",
+		"mode", body.Mode,
+		"reason", body.Reason,
+		"actor", middleware.Actor(ctx),
+	)
+
+	msg.Send(ctx, maintenance.Get())
+}
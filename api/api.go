@@ -0,0 +1,10 @@
+// Package api holds the OpenAPI document of the mint API. The Go client in
+// api/client is generated from it with go generate.
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI 3.1 document served at /openapi.json.
+//
+//go:embed openapi.json
+var Spec []byte
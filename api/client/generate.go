@@ -0,0 +1,4 @@
+package client
+
+// The client is generated from the OpenAPI document, run go generate after changing it.
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -generate types,client -package client -o client.gen.go ../openapi.json
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"mint/shared/middleware"
+	"mint/storage"
+	"mint/utils/approval"
+	"mint/utils/msg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApprovalBody defines the request payload of an approve or reject decision.
+type ApprovalBody struct {
+	Transaction string `json:"transaction" binding:"required"` // The transaction identifier of the payout awaiting approval
+	Comment     string `json:"comment"`                        // An optional comment stored with the decision
+}
+
+// handlerApprovalApprove approves a payout awaiting approval.
+func handlerApprovalApprove(ctx *gin.Context) {
+	approvalDecision(ctx, approval.Approve)
+}
+
+// handlerApprovalReject rejects a payout awaiting approval.
+func handlerApprovalReject(ctx *gin.Context) {
+	approvalDecision(ctx, approval.Reject)
+}
+
+// handlerApprovalList returns the approval decisions of a single transaction.
+func handlerApprovalList(ctx *gin.Context) {
+	transaction := ctx.Query("transaction")
+	if len(transaction) == 0 {
+		msg.InvalidFields(ctx, msg.FieldError{Field: "transaction", Rule: "required"})
+		return
+	}
+
+	result, err := storage.APPROVAL_GET_CONTEXT(ctx.Request.Context(), transaction)
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	msg.Send(ctx, map[string]any{
+		"result": result,
+	})
+}
+
+// approvalDecision binds an ApprovalBody and applies the decision on behalf of the caller.
+func approvalDecision(ctx *gin.Context, decide func(ctx context.Context, transaction, actor, comment string) error) {
+	var body ApprovalBody
+
+	// Bind the incoming JSON to ApprovalBody and validate the input according to the struct tags
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
+		return
+	}
+
+	err := decide(ctx.Request.Context(), body.Transaction, middleware.Actor(ctx), body.Comment)
+	switch {
+	case errors.Is(err, approval.ErrNotFound):
+		msg.NotFound(ctx, err.Error())
+		return
+	case errors.Is(err, approval.ErrNotAwaiting):
+		msg.Conflict(ctx, err.Error())
+		return
+	case errors.Is(err, approval.ErrSelfApproval):
+		msg.Error(ctx, msg.ErrorForbidden, err.Error())
+		return
+	case err != nil:
+		msg.Failure(ctx, err)
+		return
+	}
+
+	msg.Send(ctx, map[string]any{
+		"result": true,
+	})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"mint/config"
+	"mint/utils/metrics"
+	"mint/utils/mysql"
+	"mint/utils/ratelimit"
+	"mint/utils/signature"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisClient is the client of the Redis server shared by every instance, nil if REDIS_ADDR is empty.
+var redisClient = newRedisClient()
+
+// newRedisClient connects to the Redis server configured by REDIS_ADDR.
+func newRedisClient() *redis.Client {
+	if len(config.RedisAddr) == 0 {
+		return nil
+	}
+	return redis.NewClient(&redis.Options{
+		Addr:     config.RedisAddr,
+		Password: config.RedisPassword,
+		DB:       config.RedisDB,
+	})
+}
+
+// newCache returns the storage of the query cache: Redis when configured, so every
+// instance shares it, or the memory of this instance, whose counters are then exported.
+func newCache() mysql.Storage {
+	if redisClient == nil {
+		storage := mysql.NewBoundedInMemoryStorage(mysql.InMemoryOptions{
+			MaxEntries: config.MySQLCacheMaxEntries,
+			MaxBytes:   int64(config.MySQLCacheMaxBytes),
+			Cleanup:    config.MySQLCacheCleanupInterval,
+		})
+		metrics.RegisterCache(func() metrics.CacheStats {
+			stats := storage.Stats()
+			return metrics.CacheStats{
+				Hits:        stats.Hits,
+				Misses:      stats.Misses,
+				Evictions:   stats.Evictions,
+				Expirations: stats.Expirations,
+				Entries:     stats.Entries,
+				Bytes:       stats.Bytes,
+			}
+		})
+		return storage
+	}
+	return mysql.NewRedisStorage(redisClient, config.RedisPrefix+"cache:")
+}
+
+// newNonceStorage returns the storage of the nonces of signed requests: Redis under its own
+// prefix when configured, or an unbounded memory storage. Unlike the cache, it never evicts a
+// nonce before it expires, which would let a request be replayed within the signature window.
+// A nonce is stored with SET NX, so two instances never both accept it, without the mutex.
+func newNonceStorage() signature.NonceStorage {
+	if redisClient == nil {
+		return mysql.NewInMemoryStorage()
+	}
+	return mysql.NewRedisStorage(redisClient, config.RedisPrefix+"nonce:")
+}
+
+// newRateLimits returns the backend of the rate limit buckets named by RATE_LIMIT_BACKEND:
+// Redis under its own prefix, where a bucket is taken from atomically by every instance, or
+// the memory of this instance. Neither shares the query cache, which may evict or reset them.
+func newRateLimits() ratelimit.Backend {
+	switch config.RateLimitBackend {
+	case "memory":
+		return ratelimit.NewMemory()
+	case "redis":
+		if redisClient == nil {
+			panic("RATE_LIMIT_BACKEND=redis requires REDIS_ADDR")
+		}
+		return ratelimit.NewRedis(redisClient, config.RedisPrefix+"ratelimit:")
+	}
+	panic(fmt.Sprintf("unknown RATE_LIMIT_BACKEND %q", config.RateLimitBackend)) // Refuse to start with a misspelled backend
+}
+
+// newMutex returns the mutex guarding the query cache: Redis when MYSQL_MUTEX_ENABLED
+// and REDIS_ADDR are set, so a lock excludes every instance, or a local one.
+func newMutex() mysql.Mutex {
+	if redisClient == nil || !config.MySQLMutexEnabled {
+		return mysql.NewLocalMutex()
+	}
+	return mysql.NewRedisMutex(redisClient, config.RedisPrefix+"mutex:")
+}
+
+// newCodec returns the serialization of cached query results named by MYSQL_CACHE_CODEC.
+func newCodec() mysql.Codec {
+	codec, err := mysql.CodecByName(config.MySQLCacheCodec)
+	if err != nil {
+		panic(err) // Refuse to start with a misspelled codec
+	}
+	return codec
+}
@@ -1,9 +1,10 @@
 package main
 
 import (
-	"fmt"
 	"time"
 
+	"mint/utils/logger"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,8 +28,11 @@ func handlerReceiveSuccess(ctx *gin.Context) {
 		return
 	}
 
-	// Print the received data to the console
-	fmt.Printf("Received Success: %+v\n", body)
+	// Log the received data
+	logger.FromContext(ctx.Request.Context()).Info("callback received",
+		logger.KeyTransaction, body.Transaction,
+		"hash", body.Hash,
+	)
 
 	// Respond with a simple text message "OK"
 	ctx.String(200, "OK")
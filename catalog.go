@@ -0,0 +1,26 @@
+package main
+
+import (
+	"mint/api"
+	"mint/utils/msg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handlerOpenAPI serves the OpenAPI document of the API.
+func handlerOpenAPI(ctx *gin.Context) {
+	ctx.Data(200, "application/json; charset=utf-8", api.Spec)
+}
+
+// handlerErrors returns the error catalog so clients can map codes to HTTP statuses and types.
+func handlerErrors(ctx *gin.Context) {
+	msg.Send(ctx, map[string]any{
+		"version": msg.CatalogVersion,
+		"errors":  msg.Catalog,
+	})
+}
+
+// handlerNotFound answers requests to unknown routes with the catalog envelope.
+func handlerNotFound(ctx *gin.Context) {
+	msg.NotFound(ctx, "")
+}
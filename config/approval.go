@@ -0,0 +1,10 @@
+package config
+
+import "mint/utils/env"
+
+var (
+	// ApprovalThreshold is the payout amount above which a second person has to approve the payout.
+	// Amounts are in the smallest units of the jetton, a value of 0 disables the threshold.
+	// Environment variable: APPROVAL_THRESHOLD
+	ApprovalThreshold = env.GetEnvInt("APPROVAL_THRESHOLD", 0)
+)
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"time"
+
+	"mint/utils/env"
+)
+
+// Payout limits. Amounts are in the smallest units of the jetton, a value of 0 disables the limit.
+// Payouts over any limit are not rejected but wait for manual approval.
+var (
+	// LimitMaxPayout is the maximum amount of a single payout.
+	// Environment variable: LIMIT_MAX_PAYOUT
+	LimitMaxPayout = env.GetEnvInt("LIMIT_MAX_PAYOUT", 0)
+
+	// LimitWalletAmount is the maximum total amount paid to one destination wallet within LimitWalletWindow.
+	// Environment variable: LIMIT_WALLET_AMOUNT
+	LimitWalletAmount = env.GetEnvInt("LIMIT_WALLET_AMOUNT", 0)
+
+	// LimitWalletWindow is the rolling window of LimitWalletAmount.
+	// Environment variable: LIMIT_WALLET_WINDOW
+	LimitWalletWindow = env.GetEnvDuration("LIMIT_WALLET_WINDOW", 24*time.Hour)
+
+	// LimitHourlyAmount is the maximum total amount paid per asset within the last hour.
+	// Environment variable: LIMIT_HOURLY_AMOUNT
+	LimitHourlyAmount = env.GetEnvInt("LIMIT_HOURLY_AMOUNT", 0)
+
+	// LimitDailyAmount is the maximum total amount paid per asset within the last day.
+	// Environment variable: LIMIT_DAILY_AMOUNT
+	LimitDailyAmount = env.GetEnvInt("LIMIT_DAILY_AMOUNT", 0)
+)
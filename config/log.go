@@ -0,0 +1,13 @@
+package config
+
+import "mint/utils/env"
+
+var (
+	// LogFormat selects the log output format: "text" or "json".
+	// Environment variable: LOG_FORMAT
+	LogFormat = env.GetEnvString("LOG_FORMAT", "text")
+
+	// LogLevel sets the minimum level of logged messages: "debug", "info", "warn" or "error".
+	// Environment variable: LOG_LEVEL
+	LogLevel = env.GetEnvString("LOG_LEVEL", "info")
+)
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"time"
+
+	"mint/utils/env"
+)
+
+// Maintenance configuration
+var (
+	// MaintenanceMode is the maintenance mode put in effect at startup when none was persisted yet:
+	// "off", "drain", "pause" or "stop". A persisted mode, such as one set through the admin API, always wins.
+	// Environment variable: MAINTENANCE_MODE
+	MaintenanceMode = env.GetEnvString("MAINTENANCE_MODE", "")
+
+	// MaintenanceReloadInterval defines how often the persisted mode is reloaded, so every instance follows a change.
+	// Environment variable: MAINTENANCE_RELOAD_INTERVAL
+	MaintenanceReloadInterval = env.GetEnvDuration("MAINTENANCE_RELOAD_INTERVAL", 30*time.Second)
+)
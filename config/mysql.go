@@ -36,8 +36,31 @@ var (
 	// Environment variable: CACHE_ENABLED
 	MySQLCacheEnabled = env.GetEnvBool("MYSQL_CACHE_ENABLED", false)
 
+	// MySQLCacheMaxEntries bounds the number of results kept by the in-memory query cache, 0 means no limit.
+	// Environment variable: MYSQL_CACHE_MAX_ENTRIES
+	MySQLCacheMaxEntries = env.GetEnvInt("MYSQL_CACHE_MAX_ENTRIES", 10000)
+
+	// MySQLCacheMaxBytes bounds the size of the results kept by the in-memory query cache, 0 means no limit.
+	// Environment variable: MYSQL_CACHE_MAX_BYTES
+	MySQLCacheMaxBytes = env.GetEnvInt("MYSQL_CACHE_MAX_BYTES", 64<<20)
+
+	// MySQLCacheCleanupInterval is the interval between two removals of expired results from the in-memory query cache.
+	// Environment variable: MYSQL_CACHE_CLEANUP_INTERVAL
+	MySQLCacheCleanupInterval = env.GetEnvDuration("MYSQL_CACHE_CLEANUP_INTERVAL", time.Minute)
+
+	// MySQLCacheDelay is how long the results of cached reads, such as API keys and queue statistics, are kept.
+	// Writes evict the results they make stale, on every instance when the cache is in Redis.
+	// Environment variable: MYSQL_CACHE_DELAY
+	MySQLCacheDelay = env.GetEnvDuration("MYSQL_CACHE_DELAY", 5*time.Second)
+
+	// MySQLCacheCodec is the serialization of cached query results: json, msgpack, gob or cbor.
+	// Entries written with another codec are still read, so it can change during a rolling upgrade.
+	// Environment variable: MYSQL_CACHE_CODEC
+	MySQLCacheCodec = env.GetEnvString("MYSQL_CACHE_CODEC", "json")
+
 	// MutexEnabled specifies whether to enable the Redis-based mutex to handle cached query data for the MySQL database.
-	// Environment variable: MUTEX_ENABLED
+	// It requires REDIS_ADDR; otherwise locks only exclude the goroutines of one instance.
+	// Environment variable: MYSQL_MUTEX_ENABLED
 	MySQLMutexEnabled = env.GetEnvBool("MYSQL_MUTEX_ENABLED", false)
 
 	// QueryDuration
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"time"
+
+	"mint/utils/env"
+)
+
+// Rate limits of the withdraw API. A token bucket refills RATE_LIMIT_*_REQUESTS tokens every
+// RATE_LIMIT_*_PERIOD and holds at most RATE_LIMIT_*_BURST tokens; 0 requests disables the limit.
+var (
+	// TrustedProxies lists the comma separated addresses or CIDRs of the proxies whose
+	// X-Forwarded-For and X-Real-IP headers are trusted to find the client IP. Empty by
+	// default, so the client IP is the address of the connection and cannot be spoofed.
+	// Environment variable: TRUSTED_PROXIES
+	TrustedProxies = env.GetEnvArrayString("TRUSTED_PROXIES", ",", nil)
+
+	// RateLimitBackend selects where the buckets are kept: "memory" for every instance on its own,
+	// or "redis" to share them between instances on the server of REDIS_ADDR.
+	// Environment variable: RATE_LIMIT_BACKEND
+	RateLimitBackend = env.GetEnvString("RATE_LIMIT_BACKEND", "memory")
+
+	// RateLimitKeyRequests is the number of requests allowed per API key every RateLimitKeyPeriod.
+	// Environment variable: RATE_LIMIT_KEY_REQUESTS
+	RateLimitKeyRequests = env.GetEnvInt("RATE_LIMIT_KEY_REQUESTS", 0)
+
+	// RateLimitKeyPeriod is the refill period of the per-key bucket.
+	// Environment variable: RATE_LIMIT_KEY_PERIOD
+	RateLimitKeyPeriod = env.GetEnvDuration("RATE_LIMIT_KEY_PERIOD", time.Minute)
+
+	// RateLimitKeyBurst is the capacity of the per-key bucket, defaults to RateLimitKeyRequests.
+	// Environment variable: RATE_LIMIT_KEY_BURST
+	RateLimitKeyBurst = env.GetEnvInt("RATE_LIMIT_KEY_BURST", 0)
+
+	// RateLimitIPRequests is the number of requests allowed per client IP every RateLimitIPPeriod.
+	// Environment variable: RATE_LIMIT_IP_REQUESTS
+	RateLimitIPRequests = env.GetEnvInt("RATE_LIMIT_IP_REQUESTS", 0)
+
+	// RateLimitIPPeriod is the refill period of the per-IP bucket.
+	// Environment variable: RATE_LIMIT_IP_PERIOD
+	RateLimitIPPeriod = env.GetEnvDuration("RATE_LIMIT_IP_PERIOD", time.Minute)
+
+	// RateLimitIPBurst is the capacity of the per-IP bucket, defaults to RateLimitIPRequests.
+	// Environment variable: RATE_LIMIT_IP_BURST
+	RateLimitIPBurst = env.GetEnvInt("RATE_LIMIT_IP_BURST", 0)
+)
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"mint/utils/env"
+)
+
+// Redis configuration
+var (
+	// RedisAddr is the "host:port" of the Redis server shared by every instance.
+	// When set, the MySQL query cache is stored in Redis instead of the memory of each instance.
+	// Environment variable: REDIS_ADDR
+	RedisAddr = env.GetEnvString("REDIS_ADDR", "")
+
+	// RedisPassword is the password used to authenticate to the Redis server.
+	// Environment variable: REDIS_PASSWORD
+	RedisPassword = env.GetEnvString("REDIS_PASSWORD", "")
+
+	// RedisDB is the number of the Redis database.
+	// Environment variable: REDIS_DB
+	RedisDB = env.GetEnvInt("REDIS_DB", 0)
+
+	// RedisPrefix namespaces every key written to Redis, so several services can share a server.
+	// Environment variable: REDIS_PREFIX
+	RedisPrefix = env.GetEnvString("REDIS_PREFIX", "mint:")
+)
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"time"
+
+	"mint/utils/env"
+)
+
+// Destination screening configuration
+var (
+	// ScreeningDenylistFiles lists files with addresses that must never receive payouts, one address per line.
+	// Environment variable: SCREENING_DENYLIST_FILES (comma separated)
+	ScreeningDenylistFiles = env.GetEnvArrayString("SCREENING_DENYLIST_FILES", ",", []string{})
+
+	// ScreeningAllowlistFiles lists files with the only addresses allowed to receive payouts.
+	// When no allowlist is loaded, every address that is not denied is allowed.
+	// Environment variable: SCREENING_ALLOWLIST_FILES (comma separated)
+	ScreeningAllowlistFiles = env.GetEnvArrayString("SCREENING_ALLOWLIST_FILES", ",", []string{})
+
+	// ScreeningDatabase enables loading additional lists from the 'screening' table.
+	// Environment variable: SCREENING_DATABASE
+	ScreeningDatabase = env.GetEnvBool("SCREENING_DATABASE", false)
+
+	// ScreeningReloadInterval defines how often the lists are reloaded from files and the database.
+	// Environment variable: SCREENING_RELOAD_INTERVAL
+	ScreeningReloadInterval = env.GetEnvDuration("SCREENING_RELOAD_INTERVAL", time.Minute)
+)
@@ -1,6 +1,10 @@
 package config
 
-import "mint/utils/env"
+import (
+	"time"
+
+	"mint/utils/env"
+)
 
 var (
 	// Port defines the port number on which the server will listen.
@@ -13,13 +17,39 @@ var (
 	// If not set, it defaults to "0.0.0.0".
 	Host = env.GetEnvString("HOST", "0.0.0.0")
 
-	// Secret is used for security purposes, such as signing tokens or encrypting sensitive data.
+	// Secret is the bootstrap root API key with every scope, used to create the first named keys.
 	// This value is retrieved from the environment variable "SECRET".
-	// If the environment variable is not set, it defaults to a predefined string.
+	// If the environment variable is not set, it defaults to an empty string and the root key is disabled.
 	Secret = env.GetEnvString("SECRET", "")
 
 	// CallbackURL is used to specify the URL to which callbacks should be sent.
 	// This value is retrieved from the environment variable "CALLBACK_URL".
 	// If the environment variable is not set, it defaults to an empty string.
 	CallbackURL = env.GetEnvString("CALLBACK_URL", "")
+
+	// SignatureWindow is the maximum clock skew accepted for signed requests.
+	// Nonces are remembered for twice this duration to reject replays.
+	// This value is retrieved from the environment variable "SIGNATURE_WINDOW".
+	// If the environment variable is not set, it defaults to 5 minutes.
+	SignatureWindow = env.GetEnvDuration("SIGNATURE_WINDOW", 5*time.Minute)
+
+	// MaxBodySize is the largest body in bytes accepted from a signed request, which is read before its signature is checked.
+	// This value is retrieved from the environment variable "MAX_BODY_SIZE".
+	// If the environment variable is not set, it defaults to 1 MiB.
+	MaxBodySize = env.GetEnvInt("MAX_BODY_SIZE", 1<<20)
+
+	// SigningKey is the master key from which the signing secret of every API key is derived.
+	// This value is retrieved from the environment variable "SIGNING_KEY".
+	// If the environment variable is not set, it defaults to an empty string and signed requests are rejected.
+	SigningKey = env.GetEnvString("SIGNING_KEY", "")
+
+	// MetricsInterval defines how often queue, callback and balance gauges are refreshed.
+	// This value is retrieved from the environment variable "METRICS_INTERVAL".
+	// If the environment variable is not set, it defaults to 30 seconds.
+	MetricsInterval = env.GetEnvDuration("METRICS_INTERVAL", 30*time.Second)
+
+	// ErrorsCompat makes every error respond with HTTP 200 for clients written before the error catalog.
+	// This value is retrieved from the environment variable "ERRORS_COMPAT".
+	// If the environment variable is not set, it defaults to false and errors use their own HTTP status.
+	ErrorsCompat = env.GetEnvBool("ERRORS_COMPAT", false)
 )
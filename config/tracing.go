@@ -0,0 +1,15 @@
+package config
+
+import "mint/utils/env"
+
+// Tracing configuration
+var (
+	// TracingEndpoint is the URL of the OTLP/HTTP collector receiving spans, e.g. "http://localhost:4318".
+	// Tracing is disabled when empty.
+	// Environment variable: TRACING_ENDPOINT
+	TracingEndpoint = env.GetEnvString("TRACING_ENDPOINT", "")
+
+	// TracingService is the service name reported with every span.
+	// Environment variable: TRACING_SERVICE
+	TracingService = env.GetEnvString("TRACING_SERVICE", "mint")
+)
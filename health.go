@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/storage"
+	"mint/utils/logger"
+	"mint/utils/maintenance"
+	"mint/utils/msg"
+	"mint/utils/mysql"
+	"mint/utils/queue"
+	"mint/utils/wallet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readyTimeout bounds every dependency check of the readiness probe.
+const readyTimeout = 3 * time.Second
+
+// errNotLoaded is reported by the readiness probe for a dependency that was not initialized.
+var errNotLoaded = errors.New("not loaded")
+
+// Status describes the state of the service returned by the status endpoint.
+type Status struct {
+	Address     string             `json:"address"`     // Hot wallet address
+	Balances    map[string]string  `json:"balances"`    // Hot wallet balances by asset, in nano units
+	Seqno       uint64             `json:"seqno"`       // Current seqno of the hot wallet
+	Worker      string             `json:"worker"`      // State of the payout worker
+	Maintenance models.Maintenance `json:"maintenance"` // Maintenance mode in effect
+	Queue       map[string]int     `json:"queue"`       // Number of queued payouts by status
+	Backlog     int                `json:"backlog"`     // Number of undelivered callbacks
+}
+
+// handlerHealthz reports that the process is up.
+func handlerHealthz(ctx *gin.Context) {
+	ctx.JSON(200, gin.H{"status": "ok"})
+}
+
+// handlerReadyz reports whether the service can accept payouts:
+// MySQL answers a ping, the wallet is loaded and a liteserver is reachable.
+func handlerReadyz(ctx *gin.Context) {
+	checks := gin.H{}
+	ready := true
+	log := logger.FromContext(ctx.Request.Context())
+
+	// The probe is public, so the cause of a failure, which may name hosts, is only logged
+	check := func(name string, fn func(context.Context) error) {
+		c, cancel := context.WithTimeout(ctx.Request.Context(), readyTimeout)
+		defer cancel()
+
+		if err := fn(c); err != nil {
+			log.Warn("readiness check failed", "check", name, "error", err)
+			checks[name] = "failed"
+			ready = false
+			return
+		}
+		checks[name] = "ok"
+	}
+
+	check("mysql", func(c context.Context) error {
+		if mysql.Core == nil {
+			return errNotLoaded
+		}
+		return mysql.Core.DB.PingContext(c)
+	})
+	check("wallet", func(c context.Context) error {
+		if wallet.Core == nil {
+			return errNotLoaded
+		}
+		return nil
+	})
+	check("liteserver", func(c context.Context) error {
+		if wallet.Core == nil {
+			return errNotLoaded
+		}
+		return wallet.Core.Ping(c)
+	})
+
+	if !ready {
+		ctx.JSON(503, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	ctx.JSON(200, gin.H{"status": "ok", "checks": checks})
+}
+
+// handlerStatus reports the hot wallet, the worker state, the queue depth and the callback backlog.
+func handlerStatus(ctx *gin.Context) {
+	status := Status{
+		Balances:    map[string]string{},
+		Worker:      queue.State(),
+		Maintenance: maintenance.Get(),
+		Queue:       map[string]int{},
+	}
+
+	if wallet.Core != nil {
+		status.Address = wallet.Core.WalletAddress().String()
+
+		if balance, err := wallet.Core.Balance(); err != nil {
+			slog.Error("failed to read TON balance", "error", err)
+		} else {
+			status.Balances["TON"] = strconv.FormatUint(balance, 10)
+		}
+
+		if balance, err := wallet.Core.JettonBalance(config.WalletJetton); err != nil {
+			slog.Error("failed to read jetton balance", "error", err)
+		} else {
+			status.Balances[config.WalletJetton] = balance.String()
+		}
+
+		if seqno, err := wallet.Core.Seqno(); err != nil {
+			slog.Error("failed to read wallet seqno", "error", err)
+		} else {
+			status.Seqno = seqno
+		}
+	}
+
+	if stats, err := storage.QUEUE_STATS_CONTEXT(ctx.Request.Context()); err != nil {
+		slog.Error("failed to read queue depth", "error", err)
+	} else {
+		for _, item := range stats {
+			status.Queue[item.Status] = item.Count
+		}
+	}
+
+	if stats, err := storage.SUCCESS_STATS_CONTEXT(ctx.Request.Context()); err != nil {
+		slog.Error("failed to read callback backlog", "error", err)
+	} else {
+		status.Backlog = stats.Backlog
+	}
+
+	msg.Send(ctx, status)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"mint/config"
+	"mint/shared/middleware"
+	"mint/storage"
+	"mint/utils/apikey"
+	"mint/utils/msg"
+	"mint/utils/signature"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyCreateBody defines the request payload for creating a new API key.
+type KeyCreateBody struct {
+	Name   string   `json:"name" binding:"required"`   // Human readable owner of the key, recorded on payouts and in the audit trail
+	Scopes []string `json:"scopes" binding:"required"` // Scopes granted to the key: withdraw, read, admin
+}
+
+// KeyRotateBody defines the request payload for rotating the keys of a name.
+type KeyRotateBody struct {
+	Name    string `json:"name" binding:"required"` // Name of the keys to rotate
+	Overlap int    `json:"overlap" binding:"gte=0"` // Seconds during which the previous keys keep working
+}
+
+// KeyRevokeBody defines the request payload for revoking a single key.
+type KeyRevokeBody struct {
+	KeyID string `json:"key_id" binding:"required"` // Public identifier of the key to revoke
+}
+
+// KeyResponse is returned once when a key is created or rotated.
+// The key itself is not stored and cannot be retrieved later.
+type KeyResponse struct {
+	KeyID         string `json:"key_id"`
+	Key           string `json:"key"`
+	SigningSecret string `json:"signing_secret,omitempty"` // HMAC secret of signed requests, empty when SIGNING_KEY is not set
+}
+
+// newKeyResponse returns the key along with its signing secret when signing is enabled.
+func newKeyResponse(id, key string) KeyResponse {
+	res := KeyResponse{
+		KeyID: id,
+		Key:   key,
+	}
+	if len(config.SigningKey) != 0 {
+		res.SigningSecret = signature.Secret(config.SigningKey, id)
+	}
+	return res
+}
+
+// handlerKeyList returns every API key without the key hashes.
+func handlerKeyList(ctx *gin.Context) {
+	result, err := storage.API_KEY_LIST_CONTEXT(ctx.Request.Context())
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	msg.Send(ctx, map[string]any{
+		"result": result,
+	})
+}
+
+// handlerKeyCreate creates a new API key with the requested scopes.
+func handlerKeyCreate(ctx *gin.Context) {
+	var body KeyCreateBody
+
+	// Bind the incoming JSON to KeyCreateBody and validate the input according to the struct tags
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
+		return
+	}
+	if !apikey.ValidScopes(body.Scopes) {
+		msg.InvalidFields(ctx, msg.FieldError{Field: "scopes", Rule: "oneof", Param: strings.Join(apikey.Scopes, " ")})
+		return
+	}
+
+	id, key, err := apikey.Generate()
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	_, errSQL := storage.API_KEY_ADD_CONTEXT(ctx.Request.Context(), id, body.Name, apikey.Hash(key), strings.Join(body.Scopes, ","), middleware.Actor(ctx))
+	if errSQL != nil {
+		msg.Failure(ctx, errSQL)
+		return
+	}
+
+	msg.Send(ctx, newKeyResponse(id, key))
+}
+
+// handlerKeyRotate issues a new key for an existing name. The previous keys
+// of that name stay valid for the requested overlap so clients can switch over.
+func handlerKeyRotate(ctx *gin.Context) {
+	var body KeyRotateBody
+
+	// Bind the incoming JSON to KeyRotateBody and validate the input according to the struct tags
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
+		return
+	}
+
+	id, key, err := apikey.Generate()
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	expiresAt := time.Now().Add(time.Duration(body.Overlap) * time.Second)
+
+	_, errSQL := storage.API_KEY_ROTATE_CONTEXT(ctx.Request.Context(), body.Name, id, apikey.Hash(key), expiresAt, middleware.Actor(ctx))
+	if errSQL != nil {
+		msg.Failure(ctx, errSQL)
+		return
+	}
+
+	msg.Send(ctx, newKeyResponse(id, key))
+}
+
+// handlerKeyRevoke revokes a single key immediately.
+func handlerKeyRevoke(ctx *gin.Context) {
+	var body KeyRevokeBody
+
+	// Bind the incoming JSON to KeyRevokeBody and validate the input according to the struct tags
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
+		return
+	}
+
+	result, err := storage.API_KEY_REVOKE_CONTEXT(ctx.Request.Context(), body.KeyID, middleware.Actor(ctx))
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	msg.Send(ctx, map[string]any{
+		"result": result,
+	})
+}
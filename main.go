@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"mint/config"
 	"mint/shared/middleware"
+	"mint/utils/logger"
+	"mint/utils/maintenance"
+	"mint/utils/metrics"
+	"mint/utils/msg"
 	"mint/utils/mysql"
 	"mint/utils/queue"
+	"mint/utils/screening"
+	"mint/utils/tracing"
 	"mint/utils/wallet"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -15,6 +24,14 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// cache and mutex serve the MySQL query cache. Nonces of signed requests and rate limits have
+// their own storage, which is never evicted or reset with the cache.
+var (
+	cache  = newCache()
+	mutex  = newMutex()
+	nonces = newNonceStorage()
+)
+
 // mysqlConfig defines MySQL configuration using values from the config package.
 // This includes key parameters needed for establishing connections and optimizing performance.
 var mysqlConfig = mysql.Options{
@@ -25,61 +42,116 @@ var mysqlConfig = mysql.Options{
 	Port:           config.MySQLPort,           // MySQL server port
 	MaxConnections: config.MySQLMaxConnections, // Max concurrent connections to MySQL
 	CacheEnabled:   config.MySQLCacheEnabled,   // Enable/disable query caching
-	Cache:          mysql.NewInMemoryStorage(), // Storage for caching queries
-	Mutex:          mysql.NewLocalMutex(),      // Mutex for resource coordination
+	Cache:          cache,                      // Storage for caching queries
+	Mutex:          mutex,                      // Mutex for resource coordination
+	Codec:          newCodec(),                 // Serialization of cached query results
+	Observer:       metrics.MySQL{},            // Prometheus metrics of queries and cache lookups
 }
 
 func main() {
 
+	// Configure the structured logger used by every package
+	slog.SetDefault(logger.New(os.Stdout, config.LogFormat, config.LogLevel))
+
 	// Load environment variables from a .env file if it exists, skip if not found
 	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found") // Log the absence as informational, not as an error
+		slog.Info("no .env file found") // Log the absence as informational, not as an error
 	}
 
 	// Use defer and recover to handle panics gracefully.
 	// Defer ensures the function is called at the end of main, recovering from any panic that might occur during runtime.
 	defer func() {
 		if r := recover(); r != nil {
-			log.Println("Recovered from panic:", r) // Log the panic information.
+			slog.Error("recovered from panic", "panic", r) // Log the panic information.
 		}
 	}()
 
+	// Export spans to the OTLP collector when one is configured
+	shutdown, err := tracing.Setup(context.Background(), config.TracingEndpoint, config.TracingService)
+	if err != nil {
+		panic(err)
+	}
+	defer shutdown(context.Background())
+
 	// Initialize MySQL connection with specified configuration.
-	_, err := mysql.New(mysqlConfig)
+	_, err = mysql.New(mysqlConfig)
 	if err != nil {
 		panic(err.Error()) // Panic if MySQL initialization fails
 	}
 
-	_, err = wallet.New(config.WalletWords, "https://ton.org/global.config.json")
+	w, err := wallet.New(config.WalletWords, "https://ton.org/global.config.json")
 	if err != nil {
 		panic(err) // Log any error that occurs during wallet initialization
 	}
 
+	// Never pay out to our own wallets and load the screening lists before accepting payouts
+	screening.Default.Protect(w.WalletAddress().String(), config.WalletDestination)
+	if err := screening.Default.Reload(context.Background()); err != nil {
+		panic(err)
+	}
+	go screening.Default.Run(config.ScreeningReloadInterval, func(err error) {
+		slog.Error("failed to reload screening lists", "error", err)
+	})
+
+	// Put the persisted maintenance mode in effect before the worker starts, the one from the environment is only a default
+	if err := maintenance.Load(context.Background(), config.MaintenanceMode); err != nil {
+		panic(err)
+	}
+	go maintenance.Run(config.MaintenanceReloadInterval, func(err error) {
+		slog.Error("failed to reload maintenance mode", "error", err)
+	})
+
 	go queue.Sheldule()
 	go queue.Callback()
+	go collectMetrics(config.MetricsInterval)
 
 	gin.SetMode(gin.ReleaseMode)
 
+	// Keep answering errors with HTTP 200 for clients written before the error catalog
+	msg.Compat = config.ErrorsCompat
+
 	// Create a new Gin engine instance with default middleware: logger and recovery.
 	engine := gin.New()
 
+	// Only trust the client IP forwarded by the configured proxies, the per-IP rate limit depends on it.
+	if err := engine.SetTrustedProxies(trustedProxies(config.TrustedProxies)); err != nil {
+		panic(err) // Refuse to start with a malformed proxy address
+	}
+
+	// Assign every request an identifier and log it once it completes.
+	engine.Use(middleware.RequestID)
+
+	// Trace every request, continuing the trace of the caller when it sends one.
+	engine.Use(middleware.Trace)
+
 	// Configure CORS (Cross-Origin Resource Sharing) to manage requests from different domains.
 	engine.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},                                       // Allow requests from any origin. In production, it's better to specify allowed origins.
-		AllowMethods:     []string{"GET", "POST"},                             // Allow only GET and POST requests to come through.
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"}, // Specify which headers are allowed in requests.
-		ExposeHeaders:    []string{"Content-Length"},                          // Headers that can be exposed to the client.
-		AllowCredentials: false,                                               // Disable credentials support for security.
-		MaxAge:           12 * time.Hour,                                      // Set preflight request cache duration.
+		AllowOrigins:     []string{"*"},                                                                                                                                                          // Allow requests from any origin. In production, it's better to specify allowed origins.
+		AllowMethods:     []string{"GET", "POST"},                                                                                                                                                // Allow only GET and POST requests to come through.
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Mint-Key", "X-Mint-Timestamp", "X-Mint-Nonce", "X-Mint-Signature", "X-Request-ID", "traceparent", "tracestate"}, // Specify which headers are allowed in requests.
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "Retry-After"},                                                                                                              // Headers that can be exposed to the client.
+		AllowCredentials: false,                                                                                                                                                                  // Disable credentials support for security.
+		MaxAge:           12 * time.Hour,                                                                                                                                                         // Set preflight request cache duration.
 	}))
 
-	// Define a POST route to handle withdrawal requests.
-	engine.POST("withdraw", middleware.Secret, handlerWithdraw)
-	engine.POST("callback", handlerReceiveSuccess)
+	// Register every route of the API.
+	routes(engine)
 
 	// Attempt to run the server on the specified host and port.
 	// fmt.Sprintf is used to create a formatted string for the address.
 	if err := engine.Run(fmt.Sprintf("%v:%v", config.Host, config.Port)); err != nil {
-		log.Fatalf("Failed to run server: %v", err) // Log any error that occurs while starting the server and exits the application.
+		slog.Error("failed to run server", "error", err) // Log any error that occurs while starting the server and exits the application.
+		os.Exit(1)
+	}
+}
+
+// trustedProxies drops the blank items of the TRUSTED_PROXIES list, so an empty variable trusts no proxy.
+func trustedProxies(items []string) []string {
+	proxies := []string{}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); len(item) != 0 {
+			proxies = append(proxies, item)
+		}
 	}
+	return proxies
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/storage"
+	"mint/utils/metrics"
+	"mint/utils/wallet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// handlerMetrics exposes the Prometheus metrics of the service.
+var handlerMetrics = gin.WrapH(promhttp.Handler())
+
+// collectMetrics refreshes the gauges that are read from the database and the blockchain every interval.
+func collectMetrics(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// A collection never outlasts its interval
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		collectQueueMetrics(ctx)
+		collectCallbackMetrics(ctx)
+		collectWalletMetrics()
+		cancel()
+		<-ticker.C
+	}
+}
+
+// collectQueueMetrics updates the queue depth by status and the age of the oldest pending payout.
+func collectQueueMetrics(ctx context.Context) {
+	stats, err := storage.QUEUE_STATS_CONTEXT(ctx)
+	if err != nil {
+		slog.Error("failed to collect queue metrics", "error", err)
+		return
+	}
+
+	// Reset so statuses that disappeared from the queue drop to zero
+	metrics.QueueDepth.Reset()
+	metrics.QueueOldestPending.Set(0)
+	for _, item := range stats {
+		metrics.QueueDepth.WithLabelValues(item.Status).Set(float64(item.Count))
+		if item.Status == models.StatusPending && item.Oldest != nil {
+			metrics.QueueOldestPending.Set(time.Since(*item.Oldest).Seconds())
+		}
+	}
+}
+
+// collectCallbackMetrics updates the callback backlog and the age of the oldest undelivered callback.
+func collectCallbackMetrics(ctx context.Context) {
+	stats, err := storage.SUCCESS_STATS_CONTEXT(ctx)
+	if err != nil {
+		slog.Error("failed to collect callback metrics", "error", err)
+		return
+	}
+
+	metrics.CallbackBacklog.Set(float64(stats.Backlog))
+	metrics.CallbackLag.Set(0)
+	if stats.Oldest != nil {
+		metrics.CallbackLag.Set(time.Since(*stats.Oldest).Seconds())
+	}
+}
+
+// collectWalletMetrics updates the TON and jetton balances of the hot wallet.
+func collectWalletMetrics() {
+	if wallet.Core == nil {
+		return
+	}
+
+	if balance, err := wallet.Core.Balance(); err != nil {
+		slog.Error("failed to collect TON balance", "error", err)
+	} else {
+		metrics.WalletBalance.WithLabelValues("TON").Set(float64(balance))
+	}
+
+	if balance, err := wallet.Core.JettonBalance(config.WalletJetton); err != nil {
+		slog.Error("failed to collect jetton balance", "error", err)
+	} else {
+		value, _ := new(big.Float).SetInt(balance).Float64()
+		metrics.WalletBalance.WithLabelValues(config.WalletJetton).Set(value)
+	}
+}
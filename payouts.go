@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"mint/storage"
+	"mint/utils/msg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limits for the page size of the payout history.
+const (
+	payoutsDefaultLimit = 50
+	payoutsMaxLimit     = 200
+)
+
+// PayoutsQuery defines the query parameters accepted by the payout history endpoint.
+type PayoutsQuery struct {
+	Status      string    `form:"status"`                                       // Filter by payout status
+	Wallet      string    `form:"wallet"`                                       // Filter by recipient wallet address
+	From        time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"` // Created at or after this time (RFC 3339)
+	To          time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`   // Created before this time (RFC 3339)
+	Transaction string    `form:"transaction"`                                  // Filter by transaction identifier
+	Hash        string    `form:"hash"`                                         // Filter by blockchain transaction hash
+	Asset       string    `form:"asset"`                                        // Filter by jetton address
+	Cursor      string    `form:"cursor"`                                       // Opaque cursor returned by the previous page
+	Limit       int       `form:"limit" binding:"omitempty,gt=0"`               // Page size, defaults to 50 and is capped at 200
+}
+
+// handlerPayouts returns one page of the payout history matching the query filters.
+func handlerPayouts(ctx *gin.Context) {
+	var query PayoutsQuery
+
+	// Bind the query string to PayoutsQuery and validate the input according to the struct tags
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
+		return
+	}
+
+	cursor, ok := decodeCursor(query.Cursor)
+	if !ok {
+		msg.InvalidFields(ctx, msg.FieldError{Field: "cursor", Rule: "cursor"})
+		return
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = payoutsDefaultLimit
+	}
+	if limit > payoutsMaxLimit {
+		limit = payoutsMaxLimit
+	}
+
+	// Request one extra row to find out whether there is a next page
+	payouts, err := storage.PAYOUT_LIST_CONTEXT(ctx.Request.Context(), storage.PayoutFilter{
+		Status:      query.Status,
+		Wallet:      query.Wallet,
+		From:        query.From,
+		To:          query.To,
+		Transaction: query.Transaction,
+		Hash:        query.Hash,
+		Asset:       query.Asset,
+		Cursor:      cursor,
+		Limit:       limit + 1,
+	})
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	next := ""
+	if len(payouts) > limit {
+		payouts = payouts[:limit]
+		next = encodeCursor(payouts[limit-1].ID)
+	}
+
+	msg.Send(ctx, map[string]any{
+		"items":       payouts,
+		"next_cursor": next,
+	})
+}
+
+// encodeCursor turns the id of the last returned payout into an opaque cursor.
+func encodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+// decodeCursor parses a cursor produced by encodeCursor. An empty cursor means the first page.
+func decodeCursor(cursor string) (int, bool) {
+	if len(cursor) == 0 {
+		return 0, true
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(string(raw))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
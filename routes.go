@@ -0,0 +1,71 @@
+package main
+
+import (
+	"mint/config"
+	"mint/shared/middleware"
+	"mint/utils/apikey"
+	"mint/utils/ratelimit"
+	"mint/utils/signature"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routes registers every route of the API on engine. The routes must match api/openapi.json.
+func routes(engine *gin.Engine) {
+
+	// Rate limit buckets are shared between instances through Redis when configured
+	limits := newRateLimits()
+
+	// Define a POST route to handle withdrawal requests. It is closed in maintenance and
+	// limited per client IP before and per API key after authentication.
+	engine.POST("withdraw",
+		middleware.Maintenance,
+		middleware.RateLimitIP(ratelimit.New(ratelimit.Policy{
+			Requests: config.RateLimitIPRequests,
+			Period:   config.RateLimitIPPeriod,
+			Burst:    config.RateLimitIPBurst,
+		}, limits)),
+		middleware.AuthSigned(apikey.ScopeWithdraw, signature.NewNonceStore(nonces)),
+		middleware.RateLimitKey(ratelimit.New(ratelimit.Policy{
+			Requests: config.RateLimitKeyRequests,
+			Period:   config.RateLimitKeyPeriod,
+			Burst:    config.RateLimitKeyBurst,
+		}, limits)),
+		handlerWithdraw,
+	)
+	engine.POST("callback", handlerReceiveSuccess)
+
+	// Define the probes used by orchestrators and the status report of the service.
+	engine.GET("healthz", handlerHealthz)
+	engine.GET("readyz", handlerReadyz)
+	engine.GET("status", middleware.Auth(apikey.ScopeRead), handlerStatus)
+
+	// Define the GET routes describing the API and its error codes, and answer unknown routes in the same envelope.
+	engine.GET("openapi.json", handlerOpenAPI)
+	engine.GET("errors", handlerErrors)
+	engine.NoRoute(handlerNotFound)
+
+	// Define a GET route to expose Prometheus metrics.
+	engine.GET("metrics", middleware.Auth(apikey.ScopeRead), handlerMetrics)
+
+	// Define a GET route to search the payout history.
+	engine.GET("payouts", middleware.Auth(apikey.ScopeRead), handlerPayouts)
+
+	// Define the admin routes used to manage queued payouts.
+	admin := engine.Group("admin", middleware.Auth(apikey.ScopeAdmin))
+	admin.POST("queue/cancel", handlerQueueCancel)
+	admin.POST("queue/hold", handlerQueueHold)
+	admin.POST("queue/release", handlerQueueRelease)
+	admin.POST("queue/priority", handlerQueuePriority)
+	admin.GET("audit", handlerAuditList)
+	admin.GET("approvals", handlerApprovalList)
+	admin.POST("approvals/approve", handlerApprovalApprove)
+	admin.POST("approvals/reject", handlerApprovalReject)
+	admin.POST("screening/reload", handlerScreeningReload)
+	admin.GET("maintenance", handlerMaintenanceGet)
+	admin.POST("maintenance", handlerMaintenanceSet)
+	admin.GET("keys", handlerKeyList)
+	admin.POST("keys", handlerKeyCreate)
+	admin.POST("keys/rotate", handlerKeyRotate)
+	admin.POST("keys/revoke", handlerKeyRevoke)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mint/api"
+	"mint/api/client"
+	"mint/config"
+	"mint/utils/msg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spec is the subset of the OpenAPI document checked by the tests.
+type spec struct {
+	OpenAPI  string                                `json:"openapi"`
+	Paths    map[string]map[string]json.RawMessage `json:"paths"`
+	Webhooks map[string]json.RawMessage            `json:"webhooks"`
+}
+
+func loadSpec(t *testing.T) spec {
+	var doc spec
+	require.NoError(t, json.Unmarshal(api.Spec, &doc))
+	return doc
+}
+
+func newEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	routes(engine)
+	return engine
+}
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	doc := loadSpec(t)
+
+	documented := []string{}
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	registered := []string{}
+	for _, route := range newEngine().Routes() {
+		registered = append(registered, route.Method+" "+route.Path)
+	}
+
+	assert.ElementsMatch(t, registered, documented)
+	assert.Contains(t, doc.Webhooks, "payoutSent")
+}
+
+func TestSpecReferences(t *testing.T) {
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(api.Spec, &doc))
+
+	var walk func(node any)
+	walk = func(node any) {
+		switch value := node.(type) {
+		case map[string]any:
+			if ref, ok := value["$ref"].(string); ok {
+				target := any(doc)
+				for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+					object, ok := target.(map[string]any)
+					require.True(t, ok, ref)
+					target, ok = object[part]
+					require.True(t, ok, ref)
+				}
+			}
+			for _, child := range value {
+				walk(child)
+			}
+		case []any:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+}
+
+func TestSpecErrorTypes(t *testing.T) {
+	var doc struct {
+		Components struct {
+			Schemas struct {
+				ErrorType struct {
+					Enum []string `json:"enum"`
+				} `json:"ErrorType"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(api.Spec, &doc))
+
+	types := []string{}
+	for _, entry := range msg.Catalog {
+		types = append(types, entry.Type)
+	}
+	assert.Equal(t, types, doc.Components.Schemas.ErrorType.Enum)
+}
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(newEngine())
+	defer server.Close()
+
+	c, err := client.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+
+	health, err := c.HealthzWithResponse(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, health.JSON200)
+	assert.Equal(t, client.HealthStatusOk, health.JSON200.Status)
+
+	catalog, err := c.ErrorsWithResponse(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, catalog.JSON200)
+	assert.Equal(t, msg.CatalogVersion, catalog.JSON200.Response.Version)
+	assert.Len(t, catalog.JSON200.Response.Errors, len(msg.Catalog))
+
+	withdraw, err := c.WithdrawWithResponse(context.Background(), client.WithdrawJSONRequestBody{
+		Wallet:  "EQCvxJy4eG8hyHBFsZ7eePxrRsUQSFE_jpptRAYBmcG_DOGS",
+		Amount:  1,
+		Message: "test",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, withdraw.StatusCode())
+	require.NotNil(t, withdraw.JSON401)
+	assert.Equal(t, client.ErrorTypeUnauthorized, withdraw.JSON401.Error.Type)
+
+	spec, err := c.OpenapiWithResponse(context.Background())
+	require.NoError(t, err)
+	assert.JSONEq(t, string(api.Spec), string(spec.Body))
+}
+
+func TestWithdrawRateLimit(t *testing.T) {
+	requests := config.RateLimitIPRequests
+	config.RateLimitIPRequests = 1
+	defer func() { config.RateLimitIPRequests = requests }()
+
+	engine := newEngine()
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/withdraw", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/withdraw", nil))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+}
+
+func TestWithdrawAmountOverflow(t *testing.T) {
+	secret := config.Secret
+	config.Secret = "root-secret"
+	defer func() { config.Secret = secret }()
+
+	engine := newEngine()
+
+	// An amount above MaxInt64 would wrap to a negative number and pass every limit
+	body := `{"transaction":"tx","wallet":"EQ","amount":9223372036854775808,"message":"m"}`
+	request := httptest.NewRequest(http.MethodPost, "/withdraw", strings.NewReader(body))
+	request.Header.Set("Authorization", "root-secret")
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+	assert.Equal(t, msg.ErrorInvalidFields.Status, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"amount"`)
+	assert.Contains(t, recorder.Body.String(), `"lte"`)
+}
+
+func TestWithdrawInvalidWallet(t *testing.T) {
+	secret := config.Secret
+	config.Secret = "root-secret"
+	defer func() { config.Secret = secret }()
+
+	engine := newEngine()
+
+	// An address that cannot be parsed is rejected before it is screened or queued
+	body := `{"transaction":"tx","wallet":"EQ","amount":1,"message":"m"}`
+	request := httptest.NewRequest(http.MethodPost, "/withdraw", strings.NewReader(body))
+	request.Header.Set("Authorization", "root-secret")
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+	assert.Equal(t, msg.ErrorInvalidFields.Status, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"wallet"`)
+	assert.Contains(t, recorder.Body.String(), `"address"`)
+}
+
+func TestReadyzHidesCauses(t *testing.T) {
+	engine := newEngine()
+
+	// Nothing is loaded in tests, every check fails without telling why
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.JSONEq(t, `{"status":"unavailable","checks":{"mysql":"failed","wallet":"failed","liteserver":"failed"}}`, recorder.Body.String())
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"mint/utils/maintenance"
+	"mint/utils/msg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Maintenance refuses requests with ServiceWork while the maintenance mode closes the intake.
+func Maintenance(ctx *gin.Context) {
+	if maintenance.IntakeClosed() {
+		msg.ServiceWork(ctx)
+		return
+	}
+	ctx.Next()
+}
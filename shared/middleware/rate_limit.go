@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+
+	"mint/utils/logger"
+	"mint/utils/msg"
+	"mint/utils/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitIP limits requests per client IP. It should run before authentication
+// so that guessing keys is limited too.
+func RateLimitIP(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		limit(ctx, limiter, "ip_"+ctx.ClientIP())
+	}
+}
+
+// RateLimitKey limits requests per authenticated API key. It must run after Auth or AuthSigned.
+func RateLimitKey(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := APIKey(ctx)
+		if key == nil {
+			ctx.Next()
+			return
+		}
+		limit(ctx, limiter, "key_"+key.KeyID)
+	}
+}
+
+// limit takes a token for key, or responds with ManyRequest and a "Retry-After" header.
+// Requests are let through when the backend fails, so an outage of the shared storage
+// does not stop payouts.
+func limit(ctx *gin.Context, limiter *ratelimit.Limiter, key string) {
+	allowed, wait, err := limiter.Allow(key)
+	if err != nil {
+		logger.FromContext(ctx.Request.Context()).Warn("rate limiter unavailable", "error", err)
+		ctx.Next()
+		return
+	}
+
+	if !allowed {
+		// Retry-After is given in whole seconds, rounded up
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(wait.Seconds())))))
+		msg.ManyRequest(ctx)
+		return
+	}
+
+	ctx.Next()
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mint/utils/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitIP(t *testing.T) {
+	newEngine := func(proxies []string) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		engine := gin.New()
+		assert.NoError(t, engine.SetTrustedProxies(proxies))
+
+		limiter := ratelimit.New(ratelimit.Policy{Requests: 1, Period: time.Minute}, ratelimit.NewMemory())
+		engine.POST("/withdraw", RateLimitIP(limiter), func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+		return engine
+	}
+
+	// send makes a request from the address of httptest, claiming to forward the given client
+	send := func(engine *gin.Engine, forwarded string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/withdraw", nil)
+		request.Header.Set("X-Forwarded-For", forwarded)
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Untrusted Forwarded Header", func(t *testing.T) {
+		engine := newEngine(nil)
+
+		// A fresh X-Forwarded-For does not give a fresh bucket
+		assert.Equal(t, http.StatusOK, send(engine, "203.0.113.1").Code)
+		recorder := send(engine, "203.0.113.2")
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+	})
+
+	t.Run("Trusted Proxy", func(t *testing.T) {
+		engine := newEngine([]string{"192.0.2.1"})
+
+		// Behind a trusted proxy, every forwarded client has its own bucket
+		assert.Equal(t, http.StatusOK, send(engine, "203.0.113.1").Code)
+		assert.Equal(t, http.StatusOK, send(engine, "203.0.113.2").Code)
+		assert.Equal(t, http.StatusTooManyRequests, send(engine, "203.0.113.1").Code)
+	})
+}
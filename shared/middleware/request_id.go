@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"mint/utils/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestID carries the request identifier in both directions.
+const HeaderRequestID = "X-Request-ID"
+
+// RequestID assigns every request an identifier, taken from the "X-Request-ID" header
+// or generated, and returns it in the response. A logger carrying the identifier is
+// stored in the request context for handlers, and the request is logged once it completes.
+func RequestID(ctx *gin.Context) {
+	id := ctx.GetHeader(HeaderRequestID)
+	if len(id) == 0 || len(id) > 64 {
+		id = logger.NewID()
+	}
+	ctx.Header(HeaderRequestID, id)
+
+	log := slog.Default().With(logger.KeyRequestID, id)
+	ctx.Request = ctx.Request.WithContext(logger.WithContext(ctx.Request.Context(), log))
+
+	start := time.Now()
+	ctx.Next()
+
+	log.Info("request",
+		"method", ctx.Request.Method,
+		"path", ctx.Request.URL.Path,
+		"status", ctx.Writer.Status(),
+		"duration", time.Since(start),
+		"client_ip", ctx.ClientIP(),
+	)
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mint/config"
+	"mint/utils/apikey"
+	"mint/utils/mysql"
+	"mint/utils/signature"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthSignedBodyLimit(t *testing.T) {
+	size := config.MaxBodySize
+	config.MaxBodySize = 16
+	defer func() { config.MaxBodySize = size }()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	nonces := signature.NewNonceStore(mysql.NewInMemoryStorage())
+	engine.POST("/withdraw", AuthSigned(apikey.ScopeWithdraw, nonces), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	send := func(body string) int {
+		request := httptest.NewRequest(http.MethodPost, "/withdraw", strings.NewReader(body))
+		request.Header.Set(HeaderSignature, "00")
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// The body is refused before the signature is looked at
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(strings.Repeat("x", 17)))
+
+	// A body within the limit reaches the signature check, which fails without headers
+	assert.Equal(t, http.StatusUnauthorized, send(strings.Repeat("x", 16)))
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+
+	"mint/utils/logger"
+	"mint/utils/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace starts a server span for every request, continuing the trace from the
+// incoming "traceparent" header when present, and stores it in the request context.
+// It must be registered after RequestID so the span carries the request identifier.
+func Trace(ctx *gin.Context) {
+	parent := tracing.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+
+	route := ctx.FullPath()
+	if len(route) == 0 {
+		route = ctx.Request.URL.Path
+	}
+
+	c, span := tracing.Tracer.Start(parent, fmt.Sprintf("%s %s", ctx.Request.Method, route),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", ctx.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String(logger.KeyRequestID, ctx.Writer.Header().Get(HeaderRequestID)),
+		),
+	)
+	defer span.End()
+
+	ctx.Request = ctx.Request.WithContext(c)
+	ctx.Next()
+
+	status := ctx.Writer.Status()
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if status >= 500 {
+		span.SetStatus(codes.Error, fmt.Sprint(status))
+	}
+}
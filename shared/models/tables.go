@@ -2,6 +2,25 @@ package models
 
 import "time"
 
+// Queue statuses. A payout is created as StatusPending and is picked up by the
+// worker only in that state; StatusHeld and StatusCanceled are set by admins.
+const (
+	StatusPending    = "pending"           // Waiting to be claimed by the worker
+	StatusHeld       = "held"              // Put on hold by an admin, skipped by the worker
+	StatusProcessing = "processing"        // Claimed by the worker and being sent
+	StatusCanceled   = "canceled"          // Canceled by an admin before it was claimed
+	StatusSent       = "sent"              // Sent to the blockchain and moved to the success table
+	StatusApproval   = "awaiting_approval" // Waiting for manual approval, skipped by the worker
+	StatusRejected   = "rejected"          // Rejected by an approver, never sent
+	StatusBlocked    = "blocked"           // Blocked by destination screening, never sent
+)
+
+// Approval decisions.
+const (
+	DecisionApproved = "approved"
+	DecisionRejected = "rejected"
+)
+
 // Queue represents the 'queue' table in the database.
 type Queue struct {
 	ID          int       `json:"id" db:"id"`
@@ -9,8 +28,14 @@ type Queue struct {
 	Wallet      string    `json:"wallet" db:"wallet"`
 	Amount      int       `json:"amount" db:"amount"`
 	Message     string    `json:"message" db:"message"`
+	Status      string    `json:"status" db:"status"`
+	Priority    int       `json:"priority" db:"priority"`
+	RequestedBy string    `json:"requested_by" db:"requested_by"`
+	Reason      string    `json:"reason" db:"reason"`
+	ApprovedBy  string    `json:"approved_by" db:"approved_by"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Trace       string    `json:"-" db:"trace"` // W3C "traceparent" of the intake request
 }
 
 // Success represents the 'success' table in the database.
@@ -23,4 +48,99 @@ type Success struct {
 	Hash        string    `json:"hash" db:"hash"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Trace       string    `json:"-" db:"trace"` // W3C "traceparent" of the intake request
+}
+
+// Payout represents a single row of the payout history, regardless of whether
+// the payout is still queued or has already been sent.
+type Payout struct {
+	ID          int        `json:"id" db:"id"`
+	Transaction string     `json:"transaction" db:"transaction"`
+	Wallet      string     `json:"wallet" db:"wallet"`
+	Amount      int        `json:"amount" db:"amount"`
+	Message     string     `json:"message" db:"message"`
+	Asset       string     `json:"asset" db:"asset"`
+	Status      string     `json:"status" db:"status"`
+	RequestedBy string     `json:"requested_by" db:"requested_by"`
+	Reason      string     `json:"reason" db:"reason"`
+	ApprovedBy  string     `json:"approved_by" db:"approved_by"`
+	Hash        *string    `json:"hash" db:"hash"`
+	DeliveredAt *time.Time `json:"delivered_at" db:"delivered_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Audit represents the 'audit' table in the database.
+// Every admin action on a queued payout is recorded here.
+type Audit struct {
+	ID          int       `json:"id" db:"id"`
+	Actor       string    `json:"actor" db:"actor"`
+	Action      string    `json:"action" db:"action"`
+	Transaction string    `json:"transaction" db:"transaction"`
+	Details     string    `json:"details" db:"details"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Approval represents the 'approvals' table in the database.
+// Every approve or reject decision on a payout is recorded here.
+type Approval struct {
+	ID          int       `json:"id" db:"id"`
+	Transaction string    `json:"transaction" db:"transaction"`
+	Decision    string    `json:"decision" db:"decision"`
+	Actor       string    `json:"actor" db:"actor"`
+	Comment     string    `json:"comment" db:"comment"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// ScreeningEntry represents the 'screening' table in the database.
+// Kind is either "deny" or "allow".
+type ScreeningEntry struct {
+	Address string `json:"address" db:"address"`
+	List    string `json:"list" db:"list"`
+	Kind    string `json:"kind" db:"kind"`
+}
+
+// QueueStats is one row of the QUEUE_STATS procedure: the queue size of a single status.
+type QueueStats struct {
+	Status string     `json:"status" db:"status"`
+	Count  int        `json:"count" db:"count"`
+	Oldest *time.Time `json:"oldest" db:"oldest"`
+}
+
+// CallbackStats is the result of the SUCCESS_STATS procedure: the undelivered callbacks.
+type CallbackStats struct {
+	Backlog int        `json:"backlog" db:"backlog"`
+	Oldest  *time.Time `json:"oldest" db:"oldest"`
+}
+
+// Maintenance represents the single row of the 'maintenance' table in the database.
+type Maintenance struct {
+	Mode      string    `json:"mode" db:"mode"`
+	Reason    string    `json:"reason" db:"reason"`
+	UpdatedBy string    `json:"updated_by" db:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ApiKey represents the 'api_keys' table in the database.
+// Only the hash of the key is stored; the key itself is shown once when it is created.
+type ApiKey struct {
+	ID        int        `json:"id" db:"id"`
+	KeyID     string     `json:"key_id" db:"key_id"`
+	Name      string     `json:"name" db:"name"`
+	Hash      string     `json:"-" db:"hash"`
+	Scopes    string     `json:"scopes" db:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Active reports whether the key is neither revoked nor expired at the given time.
+func (k *ApiKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil && !now.Before(*k.RevokedAt) {
+		return false
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return false
+	}
+	return true
 }
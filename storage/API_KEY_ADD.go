@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// API_KEY_ADD stores the hash of a new API key with the given scopes
+// and records the action in the audit table.
+func API_KEY_ADD(keyID, name, hash, scopes, actor string) (*bool, *mysql.MySQLError) {
+	return API_KEY_ADD_CONTEXT(context.Background(), keyID, name, hash, scopes, actor)
+}
+
+// API_KEY_ADD_CONTEXT is API_KEY_ADD bounded by ctx.
+func API_KEY_ADD_CONTEXT(ctx context.Context, keyID, name, hash, scopes, actor string) (*bool, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "API_KEY_ADD",
+		Args:       []any{keyID, name, hash, scopes, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagAPIKeys},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the add operation
+		return utils.ToPointer(true), nil
+	})
+}
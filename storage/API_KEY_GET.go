@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// API_KEY_GET returns the API key with the given public identifier, or nil if it does not exist.
+func API_KEY_GET(keyID string) (*models.ApiKey, *mysql.MySQLError) {
+	return API_KEY_GET_CONTEXT(context.Background(), keyID)
+}
+
+// API_KEY_GET_CONTEXT is API_KEY_GET bounded by ctx. The key is never cached: most codecs
+// of the cache follow the json tags, which leave out the hash the key is verified against.
+func API_KEY_GET_CONTEXT(ctx context.Context, keyID string) (*models.ApiKey, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:    "API_KEY_GET",
+		Args:    []any{keyID},
+		Timeout: config.MySQLQueryDuration,
+	}, func(rows *sql.Rows) (*models.ApiKey, *mysql.MySQLError) {
+		if !rows.Next() {
+			return nil, nil
+		}
+		key := models.ApiKey{}
+		err := rows.Scan(
+			&key.ID,
+			&key.KeyID,
+			&key.Name,
+			&key.Hash,
+			&key.Scopes,
+			&key.ExpiresAt,
+			&key.RevokedAt,
+			&key.CreatedAt,
+		)
+		if err != nil {
+			return nil, mysql.NewError(err)
+		}
+		return &key, nil
+	})
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// API_KEY_LIST returns every API key, including expired and revoked ones.
+func API_KEY_LIST() ([]*models.ApiKey, *mysql.MySQLError) {
+	return API_KEY_LIST_CONTEXT(context.Background())
+}
+
+// API_KEY_LIST_CONTEXT is API_KEY_LIST bounded by ctx.
+func API_KEY_LIST_CONTEXT(ctx context.Context) ([]*models.ApiKey, *mysql.MySQLError) {
+	keys, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "API_KEY_LIST",
+		Timeout:    config.MySQLQueryDuration,
+		CacheDelay: config.MySQLCacheDelay,
+		Tags:       []string{tagAPIKeys},
+	}, func(rows *sql.Rows) (*[]*models.ApiKey, *mysql.MySQLError) {
+		result := []*models.ApiKey{}
+		for rows.Next() {
+			key := &models.ApiKey{}
+			err := rows.Scan(
+				&key.ID,
+				&key.KeyID,
+				&key.Name,
+				&key.Hash,
+				&key.Scopes,
+				&key.ExpiresAt,
+				&key.RevokedAt,
+				&key.CreatedAt,
+			)
+			if err != nil {
+				return nil, mysql.NewError(err)
+			}
+			result = append(result, key)
+		}
+		return &result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *keys, nil
+}
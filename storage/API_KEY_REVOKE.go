@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// API_KEY_REVOKE revokes the API key with the given public identifier immediately
+// and records the action in the audit table.
+func API_KEY_REVOKE(keyID, actor string) (*bool, *mysql.MySQLError) {
+	return API_KEY_REVOKE_CONTEXT(context.Background(), keyID, actor)
+}
+
+// API_KEY_REVOKE_CONTEXT is API_KEY_REVOKE bounded by ctx.
+func API_KEY_REVOKE_CONTEXT(ctx context.Context, keyID, actor string) (*bool, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "API_KEY_REVOKE",
+		Args:       []any{keyID, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagAPIKeys},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the revoke operation
+		return utils.ToPointer(true), nil
+	})
+}
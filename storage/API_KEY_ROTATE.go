@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// API_KEY_ROTATE stores a new key for an existing key name with the same scopes,
+// makes the previous active keys of that name expire at expiresAt so both keys work
+// during the overlap, and records the action in the audit table.
+func API_KEY_ROTATE(name, keyID, hash string, expiresAt time.Time, actor string) (*bool, *mysql.MySQLError) {
+	return API_KEY_ROTATE_CONTEXT(context.Background(), name, keyID, hash, expiresAt, actor)
+}
+
+// API_KEY_ROTATE_CONTEXT is API_KEY_ROTATE bounded by ctx.
+func API_KEY_ROTATE_CONTEXT(ctx context.Context, name, keyID, hash string, expiresAt time.Time, actor string) (*bool, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "API_KEY_ROTATE",
+		Args:       []any{name, keyID, hash, expiresAt, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagAPIKeys},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the rotate operation
+		return utils.ToPointer(true), nil
+	})
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// APPROVAL_DECIDE records an approve or reject decision on a payout awaiting approval.
+// An approved payout returns to the pending state with approved_by set, a rejected one
+// becomes rejected. The procedure signals an error if the payout is not awaiting approval.
+func APPROVAL_DECIDE(transaction, decision, actor, comment string) (*bool, *mysql.MySQLError) {
+	return APPROVAL_DECIDE_CONTEXT(context.Background(), transaction, decision, actor, comment)
+}
+
+// APPROVAL_DECIDE_CONTEXT is APPROVAL_DECIDE bounded by ctx.
+func APPROVAL_DECIDE_CONTEXT(ctx context.Context, transaction, decision, actor, comment string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "APPROVAL_DECIDE",
+		Args:       []any{transaction, decision, actor, comment},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the update operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "APPROVAL_DECIDE", transaction, err)
+	return result, err
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// APPROVAL_GET returns the approval decisions of the given transaction, oldest first.
+func APPROVAL_GET(transaction string) ([]*models.Approval, *mysql.MySQLError) {
+	return APPROVAL_GET_CONTEXT(context.Background(), transaction)
+}
+
+// APPROVAL_GET_CONTEXT is APPROVAL_GET bounded by ctx.
+func APPROVAL_GET_CONTEXT(ctx context.Context, transaction string) ([]*models.Approval, *mysql.MySQLError) {
+	approvals, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "APPROVAL_GET",
+		Args:       []any{transaction},
+		Timeout:    config.MySQLQueryDuration,
+		CacheDelay: config.MySQLCacheDelay,
+		Tags:       []string{tagQueue},
+	}, func(rows *sql.Rows) (*[]*models.Approval, *mysql.MySQLError) {
+		result := []*models.Approval{}
+		for rows.Next() {
+			approval := &models.Approval{}
+			err := rows.Scan(
+				&approval.ID,
+				&approval.Transaction,
+				&approval.Decision,
+				&approval.Actor,
+				&approval.Comment,
+				&approval.CreatedAt,
+			)
+			if err != nil {
+				return nil, mysql.NewError(err)
+			}
+			result = append(result, approval)
+		}
+		return &result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *approvals, nil
+}
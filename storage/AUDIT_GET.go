@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// AUDIT_GET returns the audit records of the given transaction, oldest first.
+func AUDIT_GET(transaction string) ([]*models.Audit, *mysql.MySQLError) {
+	return AUDIT_GET_CONTEXT(context.Background(), transaction)
+}
+
+// AUDIT_GET_CONTEXT is AUDIT_GET bounded by ctx.
+func AUDIT_GET_CONTEXT(ctx context.Context, transaction string) ([]*models.Audit, *mysql.MySQLError) {
+	audits, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:    "AUDIT_GET",
+		Args:    []any{transaction},
+		Timeout: config.MySQLQueryDuration,
+	}, func(rows *sql.Rows) (*[]*models.Audit, *mysql.MySQLError) {
+		result := []*models.Audit{}
+		for rows.Next() {
+			audit := &models.Audit{}
+			err := rows.Scan(
+				&audit.ID,
+				&audit.Actor,
+				&audit.Action,
+				&audit.Transaction,
+				&audit.Details,
+				&audit.CreatedAt,
+			)
+			if err != nil {
+				return nil, mysql.NewError(err)
+			}
+			result = append(result, audit)
+		}
+		return &result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *audits, nil
+}
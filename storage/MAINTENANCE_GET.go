@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// MAINTENANCE_GET returns the persisted maintenance mode, or nil if it was never set.
+func MAINTENANCE_GET() (*models.Maintenance, *mysql.MySQLError) {
+	return MAINTENANCE_GET_CONTEXT(context.Background())
+}
+
+// MAINTENANCE_GET_CONTEXT is MAINTENANCE_GET bounded by ctx.
+func MAINTENANCE_GET_CONTEXT(ctx context.Context) (*models.Maintenance, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:    "MAINTENANCE_GET",
+		Args:    []any{},
+		Timeout: config.MySQLQueryDuration,
+	}, func(rows *sql.Rows) (*models.Maintenance, *mysql.MySQLError) {
+		if !rows.Next() {
+			return nil, nil
+		}
+		maintenance := models.Maintenance{}
+		err := rows.Scan(
+			&maintenance.Mode,
+			&maintenance.Reason,
+			&maintenance.UpdatedBy,
+			&maintenance.UpdatedAt,
+		)
+		if err != nil {
+			return nil, mysql.NewError(err)
+		}
+		return &maintenance, nil
+	})
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// MAINTENANCE_SET persists the maintenance mode on behalf of actor
+// and records the change in the audit table.
+func MAINTENANCE_SET(mode, reason, actor string) (*bool, *mysql.MySQLError) {
+	return MAINTENANCE_SET_CONTEXT(context.Background(), mode, reason, actor)
+}
+
+// MAINTENANCE_SET_CONTEXT is MAINTENANCE_SET bounded by ctx.
+func MAINTENANCE_SET_CONTEXT(ctx context.Context, mode, reason, actor string) (*bool, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:    "MAINTENANCE_SET",
+		Args:    []any{mode, reason, actor},
+		Timeout: config.MySQLQueryDuration,
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the set operation
+		return utils.ToPointer(true), nil
+	})
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// PayoutFilter narrows down the payout history returned by PAYOUT_LIST.
+// Zero values mean "no filter" and are passed to the procedure as NULL.
+type PayoutFilter struct {
+	Status      string    // Payout status, see the models.Status* constants
+	Wallet      string    // Recipient wallet address
+	From        time.Time // Lower bound of created_at, inclusive
+	To          time.Time // Upper bound of created_at, exclusive
+	Transaction string    // Transaction identifier
+	Hash        string    // Blockchain transaction hash
+	Asset       string    // Jetton address of the payout
+	Cursor      int       // Return only payouts with an id lower than this value
+	Limit       int       // Maximum number of payouts to return
+}
+
+// PAYOUT_LIST returns the payout history matching the filter, newest first.
+// Pagination is keyset based: the next page starts below the id of the last returned row.
+func PAYOUT_LIST(filter PayoutFilter) ([]*models.Payout, *mysql.MySQLError) {
+	return PAYOUT_LIST_CONTEXT(context.Background(), filter)
+}
+
+// PAYOUT_LIST_CONTEXT is PAYOUT_LIST bounded by ctx.
+func PAYOUT_LIST_CONTEXT(ctx context.Context, filter PayoutFilter) ([]*models.Payout, *mysql.MySQLError) {
+	payouts, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec: "PAYOUT_LIST",
+		Args: []any{
+			nullString(filter.Status),
+			nullString(filter.Wallet),
+			nullTime(filter.From),
+			nullTime(filter.To),
+			nullString(filter.Transaction),
+			nullString(filter.Hash),
+			nullString(filter.Asset),
+			nullInt(filter.Cursor),
+			filter.Limit,
+		},
+		Timeout:    config.MySQLQueryDuration,
+		CacheDelay: config.MySQLCacheDelay,
+		Tags:       []string{tagQueue},
+	}, func(rows *sql.Rows) (*[]*models.Payout, *mysql.MySQLError) {
+		result := []*models.Payout{}
+		for rows.Next() {
+			payout := &models.Payout{}
+			err := rows.Scan(
+				&payout.ID,
+				&payout.Transaction,
+				&payout.Wallet,
+				&payout.Amount,
+				&payout.Message,
+				&payout.Asset,
+				&payout.Status,
+				&payout.RequestedBy,
+				&payout.Reason,
+				&payout.ApprovedBy,
+				&payout.Hash,
+				&payout.DeliveredAt,
+				&payout.CreatedAt,
+				&payout.UpdatedAt,
+			)
+			if err != nil {
+				return nil, mysql.NewError(err)
+			}
+			result = append(result, payout)
+		}
+		return &result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *payouts, nil
+}
+
+// nullString converts an empty string into a NULL procedure argument.
+func nullString(value string) any {
+	if len(value) == 0 {
+		return nil
+	}
+	return value
+}
+
+// nullTime converts a zero time into a NULL procedure argument.
+func nullTime(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value
+}
+
+// nullInt converts a zero integer into a NULL procedure argument.
+func nullInt(value int) any {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
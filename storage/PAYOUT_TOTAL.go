@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mint/config"
+	"mint/utils/mysql"
+)
+
+// PAYOUT_TOTAL returns the total amount of the asset paid since the given time,
+// optionally only to one wallet. Canceled payouts are never counted; when sentOnly
+// is set, payouts that are still queued or awaiting approval are skipped as well.
+func PAYOUT_TOTAL(asset, wallet string, since time.Time, sentOnly bool) (int64, *mysql.MySQLError) {
+	return PAYOUT_TOTAL_CONTEXT(context.Background(), asset, wallet, since, sentOnly)
+}
+
+// PAYOUT_TOTAL_CONTEXT is PAYOUT_TOTAL bounded by ctx.
+func PAYOUT_TOTAL_CONTEXT(ctx context.Context, asset, wallet string, since time.Time, sentOnly bool) (int64, *mysql.MySQLError) {
+	total, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:    "PAYOUT_TOTAL",
+		Args:    []any{asset, nullString(wallet), since, sentOnly},
+		Timeout: config.MySQLQueryDuration,
+	}, func(rows *sql.Rows) (*int64, *mysql.MySQLError) {
+		var total sql.NullInt64
+		if rows.Next() {
+			if err := rows.Scan(&total); err != nil {
+				return nil, mysql.NewError(err)
+			}
+		}
+		return &total.Int64, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return *total, nil
+}
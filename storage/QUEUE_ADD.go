@@ -1,20 +1,28 @@
 package storage
 
 import (
-	"database/sql"
+	"context"
 
 	"mint/config"
-	"mint/utils"
 	"mint/utils/mysql"
 )
 
-func QUEUE_ADD(transaction, wallet string, amount int64, message string) (*bool, *mysql.MySQLError) {
-	return mysql.Query(mysql.Core, mysql.Params{
-		Exec:    "QUEUE_ADD",
-		Args:    []any{transaction, wallet, amount, message},
-		Timeout: config.MySQLQueryDuration,
-	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
-		// Returning false since no rows are expected in the add operation
-		return utils.ToPointer(true), nil
+// QUEUE_ADD adds a payout of the given asset to the queue on behalf of the named API key.
+// The status is either pending or awaiting approval, in which case reason explains why.
+// trace is the "traceparent" of the intake span, stored so the worker can link its spans to it.
+// The result carries the id of the inserted row.
+func QUEUE_ADD(transaction, wallet string, amount int64, message, asset, requester, status, reason, trace string) (*mysql.Result, *mysql.MySQLError) {
+	return QUEUE_ADD_CONTEXT(context.Background(), transaction, wallet, amount, message, asset, requester, status, reason, trace)
+}
+
+// QUEUE_ADD_CONTEXT is QUEUE_ADD bounded by ctx.
+func QUEUE_ADD_CONTEXT(ctx context.Context, transaction, wallet string, amount int64, message, asset, requester, status, reason, trace string) (*mysql.Result, *mysql.MySQLError) {
+	result, err := mysql.ExecContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_ADD",
+		Args:       []any{transaction, wallet, amount, message, asset, requester, status, reason, trace},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
 	})
+	logFailure(ctx, "QUEUE_ADD", transaction, err)
+	return result, err
 }
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// QUEUE_BLOCK marks a queued payout as blocked by screening with the given reason
+// and records the action in the audit table. Blocked payouts are never sent.
+func QUEUE_BLOCK(transaction, reason, actor string) (*bool, *mysql.MySQLError) {
+	return QUEUE_BLOCK_CONTEXT(context.Background(), transaction, reason, actor)
+}
+
+// QUEUE_BLOCK_CONTEXT is QUEUE_BLOCK bounded by ctx.
+func QUEUE_BLOCK_CONTEXT(ctx context.Context, transaction, reason, actor string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_BLOCK",
+		Args:       []any{transaction, reason, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the update operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "QUEUE_BLOCK", transaction, err)
+	return result, err
+}
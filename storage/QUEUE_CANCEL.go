@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// QUEUE_CANCEL marks a pending or held payout as canceled and records the action
+// in the audit table. The procedure signals an error if the payout was already claimed.
+func QUEUE_CANCEL(transaction, actor string) (*bool, *mysql.MySQLError) {
+	return QUEUE_CANCEL_CONTEXT(context.Background(), transaction, actor)
+}
+
+// QUEUE_CANCEL_CONTEXT is QUEUE_CANCEL bounded by ctx.
+func QUEUE_CANCEL_CONTEXT(ctx context.Context, transaction, actor string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_CANCEL",
+		Args:       []any{transaction, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the cancel operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "QUEUE_CANCEL", transaction, err)
+	return result, err
+}
@@ -1,20 +1,23 @@
 package storage
 
 import (
-	"database/sql"
+	"context"
 
 	"mint/config"
-	"mint/utils"
 	"mint/utils/mysql"
 )
 
-func QUEUE_DELETE(transaction string) (*bool, *mysql.MySQLError) {
-	return mysql.Query(mysql.Core, mysql.Params{
-		Exec:    "QUEUE_DELETE",
-		Args:    []any{transaction},
-		Timeout: config.MySQLQueryDuration,
-	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
-		// Returning false since no rows are expected in the delete operation
-		return utils.ToPointer(true), nil
+// QUEUE_DELETE removes a payout from the queue. The result tells whether a row was deleted.
+func QUEUE_DELETE(transaction string) (*mysql.Result, *mysql.MySQLError) {
+	return QUEUE_DELETE_CONTEXT(context.Background(), transaction)
+}
+
+// QUEUE_DELETE_CONTEXT is QUEUE_DELETE bounded by ctx.
+func QUEUE_DELETE_CONTEXT(ctx context.Context, transaction string) (*mysql.Result, *mysql.MySQLError) {
+	return mysql.ExecContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_DELETE",
+		Args:       []any{transaction},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
 	})
 }
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// QUEUE_FIND returns the queued payout with the given transaction identifier,
+// or nil if there is no such payout in the queue.
+func QUEUE_FIND(transaction string) (*models.Queue, *mysql.MySQLError) {
+	return QUEUE_FIND_CONTEXT(context.Background(), transaction)
+}
+
+// QUEUE_FIND_CONTEXT is QUEUE_FIND bounded by ctx.
+func QUEUE_FIND_CONTEXT(ctx context.Context, transaction string) (*models.Queue, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:    "QUEUE_FIND",
+		Args:    []any{transaction},
+		Timeout: config.MySQLQueryDuration,
+	}, func(rows *sql.Rows) (*models.Queue, *mysql.MySQLError) {
+		if !rows.Next() {
+			return nil, nil
+		}
+		queue := models.Queue{}
+		err := rows.Scan(
+			&queue.ID,
+			&queue.Transaction,
+			&queue.Wallet,
+			&queue.Amount,
+			&queue.Message,
+			&queue.Status,
+			&queue.Priority,
+			&queue.RequestedBy,
+			&queue.Reason,
+			&queue.ApprovedBy,
+			&queue.CreatedAt,
+			&queue.UpdatedAt,
+		)
+		if err != nil {
+			return nil, mysql.NewError(err)
+		}
+		return &queue, nil
+	})
+	logFailure(ctx, "QUEUE_FIND", transaction, err)
+	return result, err
+}
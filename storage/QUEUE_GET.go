@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 
 	"mint/config"
@@ -8,8 +9,15 @@ import (
 	"mint/utils/mysql"
 )
 
+// QUEUE_GET returns up to limit pending payouts, highest priority first,
+// together with the trace context stored by QUEUE_ADD.
 func QUEUE_GET(limit int) (*[]models.Queue, *mysql.MySQLError) {
-	return mysql.Query(mysql.Core, mysql.Params{
+	return QUEUE_GET_CONTEXT(context.Background(), limit)
+}
+
+// QUEUE_GET_CONTEXT is QUEUE_GET bounded by ctx.
+func QUEUE_GET_CONTEXT(ctx context.Context, limit int) (*[]models.Queue, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
 		Exec:    "QUEUE_GET",
 		Args:    []interface{}{limit},
 		Timeout: config.MySQLQueryDuration,
@@ -24,8 +32,14 @@ func QUEUE_GET(limit int) (*[]models.Queue, *mysql.MySQLError) {
 					&queue.Wallet,
 					&queue.Amount,
 					&queue.Message,
+					&queue.Status,
+					&queue.Priority,
+					&queue.RequestedBy,
+					&queue.Reason,
+					&queue.ApprovedBy,
 					&queue.CreatedAt,
 					&queue.UpdatedAt,
+					&queue.Trace,
 				)
 				if err != nil {
 					return nil, mysql.NewError(err)
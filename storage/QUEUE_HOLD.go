@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// QUEUE_HOLD puts a pending payout on hold so the worker skips it,
+// and records the action in the audit table.
+func QUEUE_HOLD(transaction, actor string) (*bool, *mysql.MySQLError) {
+	return QUEUE_HOLD_CONTEXT(context.Background(), transaction, actor)
+}
+
+// QUEUE_HOLD_CONTEXT is QUEUE_HOLD bounded by ctx.
+func QUEUE_HOLD_CONTEXT(ctx context.Context, transaction, actor string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_HOLD",
+		Args:       []any{transaction, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the hold operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "QUEUE_HOLD", transaction, err)
+	return result, err
+}
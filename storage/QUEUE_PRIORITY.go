@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// QUEUE_PRIORITY sets the priority of a queued payout and records the action
+// in the audit table. QUEUE_GET returns payouts with a higher priority first.
+func QUEUE_PRIORITY(transaction string, priority int, actor string) (*bool, *mysql.MySQLError) {
+	return QUEUE_PRIORITY_CONTEXT(context.Background(), transaction, priority, actor)
+}
+
+// QUEUE_PRIORITY_CONTEXT is QUEUE_PRIORITY bounded by ctx.
+func QUEUE_PRIORITY_CONTEXT(ctx context.Context, transaction string, priority int, actor string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_PRIORITY",
+		Args:       []any{transaction, priority, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the update operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "QUEUE_PRIORITY", transaction, err)
+	return result, err
+}
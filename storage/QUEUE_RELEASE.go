@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// QUEUE_RELEASE returns a held payout to the pending state,
+// and records the action in the audit table.
+func QUEUE_RELEASE(transaction, actor string) (*bool, *mysql.MySQLError) {
+	return QUEUE_RELEASE_CONTEXT(context.Background(), transaction, actor)
+}
+
+// QUEUE_RELEASE_CONTEXT is QUEUE_RELEASE bounded by ctx.
+func QUEUE_RELEASE_CONTEXT(ctx context.Context, transaction, actor string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_RELEASE",
+		Args:       []any{transaction, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the release operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "QUEUE_RELEASE", transaction, err)
+	return result, err
+}
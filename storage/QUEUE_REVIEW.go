@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// QUEUE_REVIEW moves a queued payout into the awaiting approval state with the given reason
+// and records the action in the audit table. It is used when a payout fails a check right before sending.
+func QUEUE_REVIEW(transaction, reason, actor string) (*bool, *mysql.MySQLError) {
+	return QUEUE_REVIEW_CONTEXT(context.Background(), transaction, reason, actor)
+}
+
+// QUEUE_REVIEW_CONTEXT is QUEUE_REVIEW bounded by ctx.
+func QUEUE_REVIEW_CONTEXT(ctx context.Context, transaction, reason, actor string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_REVIEW",
+		Args:       []any{transaction, reason, actor},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the update operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "QUEUE_REVIEW", transaction, err)
+	return result, err
+}
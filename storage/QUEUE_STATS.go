@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// QUEUE_STATS returns the number of queued payouts and the creation time of the oldest one by status.
+func QUEUE_STATS() ([]*models.QueueStats, *mysql.MySQLError) {
+	return QUEUE_STATS_CONTEXT(context.Background())
+}
+
+// QUEUE_STATS_CONTEXT is QUEUE_STATS bounded by ctx.
+func QUEUE_STATS_CONTEXT(ctx context.Context) ([]*models.QueueStats, *mysql.MySQLError) {
+	stats, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_STATS",
+		Timeout:    config.MySQLQueryDuration,
+		CacheDelay: config.MySQLCacheDelay,
+		Tags:       []string{tagQueue},
+	}, func(rows *sql.Rows) (*[]*models.QueueStats, *mysql.MySQLError) {
+		result := []*models.QueueStats{}
+		for rows.Next() {
+			item := &models.QueueStats{}
+			err := rows.Scan(
+				&item.Status,
+				&item.Count,
+				&item.Oldest,
+			)
+			if err != nil {
+				return nil, mysql.NewError(err)
+			}
+			result = append(result, item)
+		}
+		return &result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *stats, nil
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 
 	"mint/config"
@@ -11,12 +12,20 @@ import (
 // QUEUE_SUCCESS executes a transaction where a record is added to the success table
 // and removed from the queue table based on the transaction identifier.
 func QUEUE_SUCCESS(transaction, hash string) (*bool, *mysql.MySQLError) {
-	return mysql.Query(mysql.Core, mysql.Params{
-		Exec:    "QUEUE_SUCCESS",
-		Args:    []any{transaction, hash},
-		Timeout: config.MySQLQueryDuration,
+	return QUEUE_SUCCESS_CONTEXT(context.Background(), transaction, hash)
+}
+
+// QUEUE_SUCCESS_CONTEXT is QUEUE_SUCCESS bounded by ctx.
+func QUEUE_SUCCESS_CONTEXT(ctx context.Context, transaction, hash string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "QUEUE_SUCCESS",
+		Args:       []any{transaction, hash},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue, tagCallbacks},
 	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
 		// Returning false since no rows are expected in the transaction operation
 		return utils.ToPointer(true), nil
 	})
+	logFailure(ctx, "QUEUE_SUCCESS", transaction, err)
+	return result, err
 }
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// SCREENING_ADD records the outcome of screening the destination of a payout,
+// together with the list that matched, if any.
+func SCREENING_ADD(transaction, wallet, outcome, list string) (*bool, *mysql.MySQLError) {
+	return SCREENING_ADD_CONTEXT(context.Background(), transaction, wallet, outcome, list)
+}
+
+// SCREENING_ADD_CONTEXT is SCREENING_ADD bounded by ctx.
+func SCREENING_ADD_CONTEXT(ctx context.Context, transaction, wallet, outcome, list string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:    "SCREENING_ADD",
+		Args:    []any{transaction, wallet, outcome, list},
+		Timeout: config.MySQLQueryDuration,
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the add operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "SCREENING_ADD", transaction, err)
+	return result, err
+}
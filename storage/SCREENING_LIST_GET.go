@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// SCREENING_LIST_GET returns every address of the screening lists stored in the database.
+func SCREENING_LIST_GET() ([]*models.ScreeningEntry, *mysql.MySQLError) {
+	return SCREENING_LIST_GET_CONTEXT(context.Background())
+}
+
+// SCREENING_LIST_GET_CONTEXT is SCREENING_LIST_GET bounded by ctx.
+func SCREENING_LIST_GET_CONTEXT(ctx context.Context) ([]*models.ScreeningEntry, *mysql.MySQLError) {
+	entries, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:    "SCREENING_LIST_GET",
+		Timeout: config.MySQLQueryDuration,
+	}, func(rows *sql.Rows) (*[]*models.ScreeningEntry, *mysql.MySQLError) {
+		result := []*models.ScreeningEntry{}
+		for rows.Next() {
+			entry := &models.ScreeningEntry{}
+			err := rows.Scan(
+				&entry.Address,
+				&entry.List,
+				&entry.Kind,
+			)
+			if err != nil {
+				return nil, mysql.NewError(err)
+			}
+			result = append(result, entry)
+		}
+		return &result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *entries, nil
+}
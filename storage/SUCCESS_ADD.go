@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 
 	"mint/config"
@@ -9,10 +10,16 @@ import (
 )
 
 func SUCCESS_ADD(limit int) (*bool, *mysql.MySQLError) {
-	return mysql.Query(mysql.Core, mysql.Params{
-		Exec:    "SUCCESS_GET",
-		Args:    []any{limit},
-		Timeout: config.MySQLQueryDuration,
+	return SUCCESS_ADD_CONTEXT(context.Background(), limit)
+}
+
+// SUCCESS_ADD_CONTEXT is SUCCESS_ADD bounded by ctx.
+func SUCCESS_ADD_CONTEXT(ctx context.Context, limit int) (*bool, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "SUCCESS_GET",
+		Args:       []any{limit},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagCallbacks},
 	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
 		return utils.ToPointer(true), nil
 	})
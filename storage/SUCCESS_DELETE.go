@@ -1,20 +1,23 @@
 package storage
 
 import (
-	"database/sql"
+	"context"
 
 	"mint/config"
-	"mint/utils"
 	"mint/utils/mysql"
 )
 
-func SUCCESS_DELETE(hash string) (*bool, *mysql.MySQLError) {
-	return mysql.Query(mysql.Core, mysql.Params{
-		Exec:    "SUCCESS_DELETE",
-		Args:    []any{hash},
-		Timeout: config.MySQLQueryDuration,
-	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
-		// Returning false since no rows are expected in the delete operation
-		return utils.ToPointer(true), nil
+// SUCCESS_DELETE removes a callback by hash. The result tells whether a row was deleted.
+func SUCCESS_DELETE(hash string) (*mysql.Result, *mysql.MySQLError) {
+	return SUCCESS_DELETE_CONTEXT(context.Background(), hash)
+}
+
+// SUCCESS_DELETE_CONTEXT is SUCCESS_DELETE bounded by ctx.
+func SUCCESS_DELETE_CONTEXT(ctx context.Context, hash string) (*mysql.Result, *mysql.MySQLError) {
+	return mysql.ExecContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "SUCCESS_DELETE",
+		Args:       []any{hash},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagCallbacks},
 	})
 }
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/utils"
+	"mint/utils/mysql"
+)
+
+// SUCCESS_DELIVERED marks the callback of a sent payout as delivered.
+// Unlike SUCCESS_DELETE the row is kept, so it stays visible in the payout history.
+func SUCCESS_DELIVERED(transaction string) (*bool, *mysql.MySQLError) {
+	return SUCCESS_DELIVERED_CONTEXT(context.Background(), transaction)
+}
+
+// SUCCESS_DELIVERED_CONTEXT is SUCCESS_DELIVERED bounded by ctx.
+func SUCCESS_DELIVERED_CONTEXT(ctx context.Context, transaction string) (*bool, *mysql.MySQLError) {
+	result, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "SUCCESS_DELIVERED",
+		Args:       []any{transaction},
+		Timeout:    config.MySQLQueryDuration,
+		Invalidate: []string{tagQueue, tagCallbacks},
+	}, func(rows *sql.Rows) (*bool, *mysql.MySQLError) {
+		// Returning true since no rows are expected in the update operation
+		return utils.ToPointer(true), nil
+	})
+	logFailure(ctx, "SUCCESS_DELIVERED", transaction, err)
+	return result, err
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 
 	"mint/config"
@@ -8,8 +9,15 @@ import (
 	"mint/utils/mysql"
 )
 
+// SUCCESS_GET returns up to limit sent payouts whose callback has not been delivered yet,
+// together with the trace context of their intake.
 func SUCCESS_GET(limit int) ([]*models.Success, *mysql.MySQLError) {
-	successes, err := mysql.Query(mysql.Core, mysql.Params{
+	return SUCCESS_GET_CONTEXT(context.Background(), limit)
+}
+
+// SUCCESS_GET_CONTEXT is SUCCESS_GET bounded by ctx.
+func SUCCESS_GET_CONTEXT(ctx context.Context, limit int) ([]*models.Success, *mysql.MySQLError) {
+	successes, err := mysql.QueryContext(ctx, mysql.Core, mysql.Params{
 		Exec:    "SUCCESS_GET",
 		Args:    []any{limit},
 		Timeout: config.MySQLQueryDuration,
@@ -23,6 +31,7 @@ func SUCCESS_GET(limit int) ([]*models.Success, *mysql.MySQLError) {
 				&success.Hash,
 				&success.CreatedAt,
 				&success.UpdatedAt,
+				&success.Trace,
 			)
 			if err != nil {
 				return nil, mysql.NewError(err)
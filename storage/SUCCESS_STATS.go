@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/utils/mysql"
+)
+
+// SUCCESS_STATS returns the number of undelivered callbacks and the creation time of the oldest one.
+func SUCCESS_STATS() (*models.CallbackStats, *mysql.MySQLError) {
+	return SUCCESS_STATS_CONTEXT(context.Background())
+}
+
+// SUCCESS_STATS_CONTEXT is SUCCESS_STATS bounded by ctx.
+func SUCCESS_STATS_CONTEXT(ctx context.Context) (*models.CallbackStats, *mysql.MySQLError) {
+	return mysql.QueryContext(ctx, mysql.Core, mysql.Params{
+		Exec:       "SUCCESS_STATS",
+		Timeout:    config.MySQLQueryDuration,
+		CacheDelay: config.MySQLCacheDelay,
+		Tags:       []string{tagCallbacks},
+	}, func(rows *sql.Rows) (*models.CallbackStats, *mysql.MySQLError) {
+		stats := models.CallbackStats{}
+		if rows.Next() {
+			err := rows.Scan(
+				&stats.Backlog,
+				&stats.Oldest,
+			)
+			if err != nil {
+				return nil, mysql.NewError(err)
+			}
+		}
+		return &stats, nil
+	})
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"context"
+
+	"mint/utils/logger"
+	"mint/utils/mysql"
+)
+
+// logFailure logs a failed procedure call together with the transaction it was called for,
+// through the logger of ctx so the line carries the request or batch it belongs to.
+func logFailure(ctx context.Context, exec, transaction string, err *mysql.MySQLError) {
+	if err != nil {
+		logger.FromContext(ctx).Error("procedure failed",
+			"exec", exec,
+			logger.KeyTransaction, transaction,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,8 @@
+package storage
+
+// Tags of the cached results, evicted by the procedures that make them stale.
+const (
+	tagQueue     = "queue"     // Payouts with their delivery, their approvals and the queue statistics
+	tagCallbacks = "callbacks" // Undelivered callbacks and their statistics
+	tagAPIKeys   = "api_keys"  // API keys
+)
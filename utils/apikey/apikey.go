@@ -0,0 +1,89 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+// Scopes that can be granted to an API key.
+const (
+	ScopeWithdraw = "withdraw" // Create payouts via POST /withdraw
+	ScopeRead     = "read"     // Read the payout history
+	ScopeAdmin    = "admin"    // Manage the queue and API keys
+)
+
+// Scopes lists every known scope.
+var Scopes = []string{ScopeWithdraw, ScopeRead, ScopeAdmin}
+
+// separator splits the public key identifier from the secret part of the key.
+const separator = "."
+
+// Generate creates a new random API key in the form "<id>.<secret>".
+// The id is public and is used to look the key up, only the hash of the full key is stored.
+func Generate() (id string, key string, err error) {
+	idBytes := make([]byte, 8)
+	if _, err = rand.Read(idBytes); err != nil {
+		return "", "", err
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+
+	id = hex.EncodeToString(idBytes)
+	return id, id + separator + hex.EncodeToString(secretBytes), nil
+}
+
+// ID extracts the public identifier from a key. It reports false if the key is malformed.
+func ID(key string) (string, bool) {
+	id, secret, ok := strings.Cut(key, separator)
+	if !ok || len(id) == 0 || len(secret) == 0 {
+		return "", false
+	}
+	return id, true
+}
+
+// Hash returns the hex encoded SHA-256 hash of the key, as stored in the database.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Equal compares two values in constant time.
+func Equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// HasScope reports whether the comma separated list of scopes contains the given scope.
+func HasScope(scopes string, scope string) bool {
+	for _, item := range strings.Split(scopes, ",") {
+		if strings.TrimSpace(item) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidScopes reports whether every scope in the list is known.
+func ValidScopes(scopes []string) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	for _, scope := range scopes {
+		known := false
+		for _, item := range Scopes {
+			if scope == item {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
+}
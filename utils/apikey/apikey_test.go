@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	id, key, err := Generate()
+	assert.NoError(t, err)
+
+	// The key starts with its public identifier
+	assert.True(t, strings.HasPrefix(key, id+"."))
+
+	parsed, ok := ID(key)
+	assert.True(t, ok)
+	assert.Equal(t, id, parsed)
+
+	// Two generated keys never collide
+	_, other, err := Generate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestID(t *testing.T) {
+	_, ok := ID("no-separator")
+	assert.False(t, ok)
+
+	_, ok = ID(".secret")
+	assert.False(t, ok)
+
+	_, ok = ID("id.")
+	assert.False(t, ok)
+
+	id, ok := ID("abc.def")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", id)
+}
+
+func TestHash(t *testing.T) {
+	// SHA-256 of "key"
+	assert.Equal(t, "2c70e12b7a0646f92279f427c7b38e7334d8e5389cff167a1dc30e73f826b683", Hash("key"))
+	assert.True(t, Equal(Hash("key"), Hash("key")))
+	assert.False(t, Equal(Hash("key"), Hash("other")))
+}
+
+func TestScopes(t *testing.T) {
+	assert.True(t, HasScope("withdraw,read", ScopeRead))
+	assert.True(t, HasScope("withdraw, read", ScopeRead))
+	assert.False(t, HasScope("withdraw,read", ScopeAdmin))
+	assert.False(t, HasScope("", ScopeAdmin))
+
+	assert.True(t, ValidScopes([]string{ScopeWithdraw, ScopeAdmin}))
+	assert.False(t, ValidScopes([]string{"root"}))
+	assert.False(t, ValidScopes(nil))
+}
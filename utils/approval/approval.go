@@ -0,0 +1,67 @@
+package approval
+
+import (
+	"context"
+	"errors"
+
+	"mint/config"
+	"mint/shared/models"
+	"mint/storage"
+)
+
+// ReasonThreshold is recorded on payouts that await approval because of their amount.
+const ReasonThreshold = "approval_threshold"
+
+var (
+	ErrNotFound     = errors.New("payout not found")
+	ErrNotAwaiting  = errors.New("payout is not awaiting approval")
+	ErrSelfApproval = errors.New("requester cannot decide on their own payout")
+)
+
+// Threshold is the amount above which a payout requires approval. A value of 0 disables it.
+var Threshold = int64(config.ApprovalThreshold)
+
+// Required reports whether a payout of the given amount requires approval.
+func Required(amount int64) bool {
+	return Threshold > 0 && amount > Threshold
+}
+
+// Approve approves a payout awaiting approval on behalf of actor, making it eligible for sending.
+func Approve(ctx context.Context, transaction, actor, comment string) error {
+	return decide(ctx, transaction, models.DecisionApproved, actor, comment)
+}
+
+// Reject rejects a payout awaiting approval on behalf of actor. It will never be sent.
+func Reject(ctx context.Context, transaction, actor, comment string) error {
+	return decide(ctx, transaction, models.DecisionRejected, actor, comment)
+}
+
+// decide validates the decision and persists it with the actor.
+func decide(ctx context.Context, transaction, decision, actor, comment string) error {
+	queue, errSQL := storage.QUEUE_FIND_CONTEXT(ctx, transaction)
+	if errSQL != nil {
+		return errSQL
+	}
+	if queue == nil {
+		return ErrNotFound
+	}
+	if err := check(queue, actor); err != nil {
+		return err
+	}
+
+	if _, errSQL = storage.APPROVAL_DECIDE_CONTEXT(ctx, transaction, decision, actor, comment); errSQL != nil {
+		return errSQL
+	}
+	return nil
+}
+
+// check ensures the payout awaits approval and the actor is not its requester.
+func check(queue *models.Queue, actor string) error {
+	if queue.Status != models.StatusApproval {
+		return ErrNotAwaiting
+	}
+	if len(actor) == 0 || queue.RequestedBy == actor {
+		return ErrSelfApproval
+	}
+	return nil
+}
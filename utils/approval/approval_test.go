@@ -0,0 +1,35 @@
+package approval
+
+import (
+	"testing"
+
+	"mint/shared/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequired(t *testing.T) {
+	defer func(value int64) { Threshold = value }(Threshold)
+
+	// A zero threshold disables approvals
+	Threshold = 0
+	assert.False(t, Required(1<<40))
+
+	Threshold = 100
+	assert.False(t, Required(100))
+	assert.True(t, Required(101))
+}
+
+func TestCheck(t *testing.T) {
+	queue := &models.Queue{
+		Status:      models.StatusApproval,
+		RequestedBy: "backend",
+	}
+
+	assert.NoError(t, check(queue, "treasurer"))
+	assert.ErrorIs(t, check(queue, "backend"), ErrSelfApproval)
+	assert.ErrorIs(t, check(queue, ""), ErrSelfApproval)
+
+	queue.Status = models.StatusPending
+	assert.ErrorIs(t, check(queue, "treasurer"), ErrNotAwaiting)
+}
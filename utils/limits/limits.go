@@ -0,0 +1,95 @@
+package limits
+
+import (
+	"context"
+	"time"
+
+	"mint/config"
+	"mint/storage"
+	"mint/utils/mysql"
+)
+
+// Reasons reported by Evaluate when a payout is over a limit.
+const (
+	ReasonMaxPayout = "limit_max_payout" // The payout itself is larger than the maximum
+	ReasonWallet    = "limit_wallet"     // The destination received too much within the wallet window
+	ReasonHourly    = "limit_hourly"     // Too much of the asset was paid within the last hour
+	ReasonDaily     = "limit_daily"      // Too much of the asset was paid within the last day
+)
+
+// Policy holds the configured payout limits. A limit of 0 is disabled.
+type Policy struct {
+	MaxPayout    int64         // Maximum amount of a single payout
+	WalletAmount int64         // Maximum total per destination within WalletWindow
+	WalletWindow time.Duration // Rolling window of WalletAmount
+	HourlyAmount int64         // Maximum total per asset within an hour
+	DailyAmount  int64         // Maximum total per asset within a day
+}
+
+// Totals holds the amounts already paid within each limit window.
+type Totals struct {
+	Wallet int64 // Paid to the destination within the wallet window
+	Hourly int64 // Paid in the asset within the last hour
+	Daily  int64 // Paid in the asset within the last day
+}
+
+// Default is the policy configured through the environment.
+var Default = Policy{
+	MaxPayout:    int64(config.LimitMaxPayout),
+	WalletAmount: int64(config.LimitWalletAmount),
+	WalletWindow: config.LimitWalletWindow,
+	HourlyAmount: int64(config.LimitHourlyAmount),
+	DailyAmount:  int64(config.LimitDailyAmount),
+}
+
+// Evaluate checks a payout of the given amount against the policy.
+// It returns the reason of the first exceeded limit, or an empty string if the payout is within limits.
+// The remaining headroom is compared instead of the new total, which could overflow for large amounts.
+func (p Policy) Evaluate(amount int64, totals Totals) string {
+	if p.MaxPayout > 0 && amount > p.MaxPayout {
+		return ReasonMaxPayout
+	}
+	if p.WalletAmount > 0 && amount > p.WalletAmount-totals.Wallet {
+		return ReasonWallet
+	}
+	if p.HourlyAmount > 0 && amount > p.HourlyAmount-totals.Hourly {
+		return ReasonHourly
+	}
+	if p.DailyAmount > 0 && amount > p.DailyAmount-totals.Daily {
+		return ReasonDaily
+	}
+	return ""
+}
+
+// Load reads the totals of the asset and destination wallet needed by the enabled limits.
+// When sentOnly is set, only payouts that were already sent are counted.
+func (p Policy) Load(ctx context.Context, asset, wallet string, sentOnly bool) (Totals, *mysql.MySQLError) {
+	var (
+		totals Totals
+		err    *mysql.MySQLError
+		now    = time.Now()
+	)
+
+	if p.WalletAmount > 0 {
+		totals.Wallet, err = storage.PAYOUT_TOTAL_CONTEXT(ctx, asset, wallet, now.Add(-p.WalletWindow), sentOnly)
+		if err != nil {
+			return totals, err
+		}
+	}
+
+	if p.HourlyAmount > 0 {
+		totals.Hourly, err = storage.PAYOUT_TOTAL_CONTEXT(ctx, asset, "", now.Add(-time.Hour), sentOnly)
+		if err != nil {
+			return totals, err
+		}
+	}
+
+	if p.DailyAmount > 0 {
+		totals.Daily, err = storage.PAYOUT_TOTAL_CONTEXT(ctx, asset, "", now.Add(-24*time.Hour), sentOnly)
+		if err != nil {
+			return totals, err
+		}
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,48 @@
+package limits
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	policy := Policy{
+		MaxPayout:    100,
+		WalletAmount: 150,
+		WalletWindow: time.Hour,
+		HourlyAmount: 500,
+		DailyAmount:  1000,
+	}
+
+	tests := []struct {
+		name     string
+		amount   int64
+		totals   Totals
+		expected string
+	}{
+		{"within limits", 100, Totals{Wallet: 50, Hourly: 400, Daily: 900}, ""},
+		{"over max payout", 101, Totals{}, ReasonMaxPayout},
+		{"over wallet window", 60, Totals{Wallet: 100}, ReasonWallet},
+		{"over hourly total", 60, Totals{Hourly: 450}, ReasonHourly},
+		{"over daily total", 60, Totals{Hourly: 100, Daily: 950}, ReasonDaily},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.Evaluate(tt.amount, tt.totals))
+		})
+	}
+
+	// Totals near MaxInt64 must not wrap around and let the payout through
+	large := Policy{WalletAmount: math.MaxInt64, HourlyAmount: math.MaxInt64, DailyAmount: math.MaxInt64}
+	assert.Equal(t, ReasonWallet, large.Evaluate(2, Totals{Wallet: math.MaxInt64 - 1}))
+	assert.Equal(t, ReasonHourly, large.Evaluate(math.MaxInt64, Totals{Hourly: 1}))
+	assert.Equal(t, ReasonDaily, large.Evaluate(math.MaxInt64, Totals{Daily: math.MaxInt64}))
+	assert.Equal(t, "", large.Evaluate(1, Totals{Wallet: math.MaxInt64 - 1, Hourly: math.MaxInt64 - 1, Daily: math.MaxInt64 - 1}))
+
+	// A zero policy has every limit disabled
+	assert.Equal(t, "", Policy{}.Evaluate(1<<40, Totals{Wallet: 1 << 40, Hourly: 1 << 40, Daily: 1 << 40}))
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Attribute keys shared by every log line of a payout.
+const (
+	KeyRequestID   = "request_id"  // Identifier of the HTTP request
+	KeyTransaction = "transaction" // Transaction identifier of the payout
+	KeyBatchID     = "batch_id"    // Identifier of the batch the worker sends the payout in
+)
+
+// contextKey is the type of the context key under which the logger is stored.
+type contextKey struct{}
+
+// New creates a structured logger writing to w.
+// The format is either "json" or "text", the level one of "debug", "info", "warn" or "error".
+// Unknown values fall back to text and info.
+func New(w io.Writer, format, level string) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level: ParseLevel(level),
+	}
+
+	if strings.EqualFold(format, "json") {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+	return slog.New(slog.NewTextHandler(w, options))
+}
+
+// ParseLevel converts a level name into a slog.Level, defaulting to info.
+func ParseLevel(level string) slog.Level {
+	var value slog.Level
+	if err := value.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return value
+}
+
+// WithContext returns a copy of ctx carrying the logger.
+func WithContext(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if log, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+			return log
+		}
+	}
+	return slog.Default()
+}
+
+// NewID returns a random identifier for requests and batches.
+func NewID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
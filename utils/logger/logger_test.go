@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		New(&buf, "json", "info").Info("payout queued", KeyTransaction, "order_1")
+
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		assert.Equal(t, "payout queued", line["msg"])
+		assert.Equal(t, "order_1", line[KeyTransaction])
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		New(&buf, "text", "warn").Info("hidden")
+		assert.Empty(t, buf.String())
+
+		New(&buf, "", "").Info("shown", KeyBatchID, "b1")
+		assert.True(t, strings.Contains(buf.String(), "batch_id=b1"))
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug, ParseLevel("debug"))
+	assert.Equal(t, slog.LevelError, ParseLevel("ERROR"))
+	assert.Equal(t, slog.LevelInfo, ParseLevel("verbose"))
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, slog.Default(), FromContext(context.Background()))
+
+	log := slog.Default().With(KeyRequestID, NewID())
+	assert.Equal(t, log, FromContext(WithContext(context.Background(), log)))
+}
+
+func TestNewID(t *testing.T) {
+	assert.Len(t, NewID(), 16)
+	assert.NotEqual(t, NewID(), NewID())
+}
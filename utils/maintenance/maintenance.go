@@ -0,0 +1,139 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"mint/shared/models"
+	"mint/storage"
+	"mint/utils/queue"
+)
+
+// Maintenance modes.
+const (
+	ModeOff   = "off"   // Payouts are accepted and sent
+	ModeDrain = "drain" // Intake answers ServiceWork while the worker sends what is queued
+	ModePause = "pause" // Payouts are accepted but the worker is paused
+	ModeStop  = "stop"  // Intake answers ServiceWork and the worker is paused
+)
+
+// ErrInvalidMode is returned for an unknown maintenance mode.
+var ErrInvalidMode = errors.New("invalid maintenance mode")
+
+// current holds the maintenance state in effect.
+var current atomic.Pointer[models.Maintenance]
+
+func init() {
+	current.Store(&models.Maintenance{Mode: ModeOff})
+}
+
+// Valid reports whether mode is a known maintenance mode.
+func Valid(mode string) bool {
+	switch mode {
+	case ModeOff, ModeDrain, ModePause, ModeStop:
+		return true
+	}
+	return false
+}
+
+// Get returns the maintenance state in effect.
+func Get() models.Maintenance {
+	return *current.Load()
+}
+
+// IntakeClosed reports whether new payouts must be refused with ServiceWork.
+func IntakeClosed() bool {
+	mode := current.Load().Mode
+	return mode == ModeDrain || mode == ModeStop
+}
+
+// WorkerPaused reports whether the payout worker must not send anything.
+func WorkerPaused() bool {
+	mode := current.Load().Mode
+	return mode == ModePause || mode == ModeStop
+}
+
+// Set persists mode on behalf of actor and puts it in effect.
+func Set(ctx context.Context, mode, reason, actor string) error {
+	if !Valid(mode) {
+		return ErrInvalidMode
+	}
+
+	if _, errSQL := storage.MAINTENANCE_SET_CONTEXT(ctx, mode, reason, actor); errSQL != nil {
+		return errSQL
+	}
+
+	apply(&models.Maintenance{
+		Mode:      mode,
+		Reason:    reason,
+		UpdatedBy: actor,
+		UpdatedAt: time.Now(),
+	})
+	return nil
+}
+
+// Load puts the persisted mode in effect. When no mode was persisted yet, fallback is
+// persisted and put in effect instead, so a mode set through the admin API is never
+// overwritten by the one given at startup. An empty fallback leaves the mode off.
+func Load(ctx context.Context, fallback string) error {
+	if len(fallback) != 0 && !Valid(fallback) {
+		return ErrInvalidMode
+	}
+
+	state, errSQL := storage.MAINTENANCE_GET_CONTEXT(ctx)
+	if errSQL != nil {
+		return errSQL
+	}
+	if state == nil && len(fallback) != 0 {
+		return Set(ctx, fallback, "set at startup", "environment")
+	}
+
+	apply(valid(state))
+	return nil
+}
+
+// Reload puts the persisted mode in effect, so every instance follows a change
+// made through another one.
+func Reload(ctx context.Context) error {
+	state, errSQL := storage.MAINTENANCE_GET_CONTEXT(ctx)
+	if errSQL != nil {
+		return errSQL
+	}
+
+	apply(valid(state))
+	return nil
+}
+
+// valid returns state, or the mode off when nothing or an unknown mode was persisted.
+func valid(state *models.Maintenance) *models.Maintenance {
+	if state == nil || !Valid(state.Mode) {
+		return &models.Maintenance{Mode: ModeOff}
+	}
+	return state
+}
+
+// Run reloads the persisted mode every interval until the process exits,
+// calling onError when a reload fails.
+func Run(interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := Reload(context.Background()); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// apply puts state in effect and pauses or resumes the payout worker accordingly.
+func apply(state *models.Maintenance) {
+	current.Store(state)
+
+	if WorkerPaused() {
+		queue.Pause()
+	} else {
+		queue.Resume()
+	}
+}
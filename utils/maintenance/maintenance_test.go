@@ -0,0 +1,52 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+
+	"mint/shared/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	for _, mode := range []string{ModeOff, ModeDrain, ModePause, ModeStop} {
+		assert.True(t, Valid(mode), mode)
+	}
+	assert.False(t, Valid(""))
+	assert.False(t, Valid("on"))
+}
+
+func TestApply(t *testing.T) {
+	defer apply(&models.Maintenance{Mode: ModeOff})
+
+	assert.Equal(t, ModeOff, Get().Mode)
+	assert.False(t, IntakeClosed())
+	assert.False(t, WorkerPaused())
+
+	cases := []struct {
+		mode   string
+		intake bool
+		worker bool
+	}{
+		{ModeDrain, true, false},
+		{ModePause, false, true},
+		{ModeStop, true, true},
+		{ModeOff, false, false},
+	}
+	for _, c := range cases {
+		apply(&models.Maintenance{Mode: c.mode, Reason: "upgrade"})
+		assert.Equal(t, c.mode, Get().Mode)
+		assert.Equal(t, "upgrade", Get().Reason)
+		assert.Equal(t, c.intake, IntakeClosed(), c.mode)
+		assert.Equal(t, c.worker, WorkerPaused(), c.mode)
+	}
+}
+
+func TestSetInvalid(t *testing.T) {
+	assert.ErrorIs(t, Set(context.Background(), "on", "", "admin"), ErrInvalidMode)
+}
+
+func TestLoadInvalidFallback(t *testing.T) {
+	assert.ErrorIs(t, Load(context.Background(), "on"), ErrInvalidMode)
+}
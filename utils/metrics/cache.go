@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CacheStats are the counters of the in-memory query cache, read at every scrape.
+type CacheStats struct {
+	Hits        uint64 // Lookups that found a valid entry
+	Misses      uint64 // Lookups of missing or expired keys
+	Evictions   uint64 // Entries evicted to stay within the bounds
+	Expirations uint64 // Expired entries removed
+	Entries     int    // Number of entries in the cache
+	Bytes       int64  // Size of the keys and values of every entry
+}
+
+// Descriptions of the metrics of the in-memory query cache.
+var (
+	cacheHits        = cacheDesc("hits_total", "Number of in-memory query cache lookups that found a valid entry.")
+	cacheMisses      = cacheDesc("misses_total", "Number of in-memory query cache lookups of missing or expired keys.")
+	cacheEvictions   = cacheDesc("evictions_total", "Number of entries evicted from the in-memory query cache to stay within its bounds.")
+	cacheExpirations = cacheDesc("expirations_total", "Number of expired entries removed from the in-memory query cache.")
+	cacheEntries     = cacheDesc("entries", "Number of entries in the in-memory query cache.")
+	cacheBytes       = cacheDesc("bytes", "Size of the keys and values in the in-memory query cache.")
+)
+
+// cacheDesc describes a metric of the in-memory query cache.
+func cacheDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "mysql_memory_cache", name), help, nil, nil)
+}
+
+// cacheCollector exports the counters returned by stats.
+type cacheCollector struct {
+	stats func() CacheStats
+}
+
+// NewCacheCollector returns a collector exporting the counters returned by stats at every scrape.
+func NewCacheCollector(stats func() CacheStats) prometheus.Collector {
+	return cacheCollector{stats: stats}
+}
+
+// RegisterCache exports the counters returned by stats with the other metrics of the service.
+func RegisterCache(stats func() CacheStats) {
+	prometheus.MustRegister(NewCacheCollector(stats))
+}
+
+func (c cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHits
+	ch <- cacheMisses
+	ch <- cacheEvictions
+	ch <- cacheExpirations
+	ch <- cacheEntries
+	ch <- cacheBytes
+}
+
+func (c cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(cacheHits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(cacheMisses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(cacheEvictions, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(cacheExpirations, prometheus.CounterValue, float64(stats.Expirations))
+	ch <- prometheus.MustNewConstMetric(cacheEntries, prometheus.GaugeValue, float64(stats.Entries))
+	ch <- prometheus.MustNewConstMetric(cacheBytes, prometheus.GaugeValue, float64(stats.Bytes))
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheCollector(t *testing.T) {
+	stats := CacheStats{Hits: 3, Misses: 2, Evictions: 1, Entries: 4, Bytes: 128}
+	collector := NewCacheCollector(func() CacheStats { return stats })
+
+	expected := `
+# HELP mint_mysql_memory_cache_entries Number of entries in the in-memory query cache.
+# TYPE mint_mysql_memory_cache_entries gauge
+mint_mysql_memory_cache_entries 4
+# HELP mint_mysql_memory_cache_hits_total Number of in-memory query cache lookups that found a valid entry.
+# TYPE mint_mysql_memory_cache_hits_total counter
+mint_mysql_memory_cache_hits_total 3
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"mint_mysql_memory_cache_entries", "mint_mysql_memory_cache_hits_total"))
+
+	// Every scrape reads the counters again
+	stats.Hits = 5
+	assert.Equal(t, 6, testutil.CollectAndCount(collector))
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(strings.ReplaceAll(expected, "total 3", "total 5")),
+		"mint_mysql_memory_cache_entries", "mint_mysql_memory_cache_hits_total"))
+}
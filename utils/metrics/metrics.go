@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// namespace prefixes every metric exported by the service.
+const namespace = "mint"
+
+// Queue metrics
+var (
+	// QueueDepth is the number of payouts in the queue by status.
+	QueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_depth",
+		Help:      "Number of queued payouts by status.",
+	}, []string{"status"})
+
+	// QueueOldestPending is the age of the oldest pending payout.
+	QueueOldestPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_oldest_pending_seconds",
+		Help:      "Age of the oldest pending payout in seconds.",
+	})
+
+	// Payouts counts payouts by asset and result: sent, confirmed or failed.
+	Payouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payouts_total",
+		Help:      "Number of payouts by asset and result.",
+	}, []string{"asset", "result"})
+)
+
+// Wallet metrics
+var (
+	// WalletSendDuration measures how long sending a batch and waiting for its transaction takes.
+	WalletSendDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "wallet_send_duration_seconds",
+		Help:      "Time to send a batch of payouts and wait for the transaction.",
+		Buckets:   []float64{1, 2.5, 5, 10, 20, 30, 60, 120},
+	})
+
+	// WalletBalance is the balance of the hot wallet by asset, "TON" for the native coin.
+	WalletBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "wallet_balance",
+		Help:      "Balance of the hot wallet in the smallest units by asset.",
+	}, []string{"asset"})
+)
+
+// Callback metrics
+var (
+	// CallbackAttempts counts callback delivery attempts.
+	CallbackAttempts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "callback_attempts_total",
+		Help:      "Number of callback delivery attempts.",
+	})
+
+	// CallbackFailures counts callback deliveries that failed or were not acknowledged with "OK".
+	CallbackFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "callback_failures_total",
+		Help:      "Number of failed callback deliveries.",
+	})
+
+	// CallbackBacklog is the number of sent payouts whose callback has not been delivered yet.
+	CallbackBacklog = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "callback_backlog",
+		Help:      "Number of undelivered callbacks.",
+	})
+
+	// CallbackLag is the age of the oldest undelivered callback.
+	CallbackLag = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "callback_lag_seconds",
+		Help:      "Age of the oldest undelivered callback in seconds.",
+	})
+)
+
+// MySQL metrics
+var (
+	// QueryDuration measures mysql.Query by the name of the called procedure.
+	QueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mysql_query_duration_seconds",
+		Help:      "Duration of MySQL queries by procedure.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"exec"})
+
+	// QueryErrors counts failed mysql.Query calls by the name of the called procedure.
+	QueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mysql_query_errors_total",
+		Help:      "Number of failed MySQL queries by procedure.",
+	}, []string{"exec"})
+
+	// CacheRequests counts lookups in the query cache by result: hit, stale or miss.
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mysql_cache_requests_total",
+		Help:      "Number of query cache lookups by result.",
+	}, []string{"result"})
+)
@@ -0,0 +1,20 @@
+package metrics
+
+import "time"
+
+// MySQL exports the queries and cache lookups of the MySQL layer. It is set as the
+// Observer of mysql.Options, which keeps that package free of Prometheus.
+type MySQL struct{}
+
+// ObserveQuery records the duration of a statement by label and counts it if it failed.
+func (MySQL) ObserveQuery(label string, duration time.Duration, failed bool) {
+	QueryDuration.WithLabelValues(label).Observe(duration.Seconds())
+	if failed {
+		QueryErrors.WithLabelValues(label).Inc()
+	}
+}
+
+// ObserveCache counts a lookup in the query cache by result.
+func (MySQL) ObserveCache(result string) {
+	CacheRequests.WithLabelValues(result).Inc()
+}
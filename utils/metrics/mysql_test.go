@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQL(t *testing.T) {
+	errors := testutil.ToFloat64(QueryErrors.WithLabelValues("METRICS_TEST"))
+	hits := testutil.ToFloat64(CacheRequests.WithLabelValues("hit"))
+
+	MySQL{}.ObserveQuery("METRICS_TEST", time.Millisecond, false)
+	MySQL{}.ObserveQuery("METRICS_TEST", time.Millisecond, true)
+	MySQL{}.ObserveCache("hit")
+
+	assert.Equal(t, errors+1, testutil.ToFloat64(QueryErrors.WithLabelValues("METRICS_TEST")))
+	assert.Equal(t, hits+1, testutil.ToFloat64(CacheRequests.WithLabelValues("hit")))
+	assert.Equal(t, 1, testutil.CollectAndCount(QueryDuration, "mint_mysql_query_duration_seconds"))
+}
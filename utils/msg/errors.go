@@ -1,91 +1,179 @@
 package msg
 
-// ErrorInvalidProtocol contains a pre-serialized message pack-format error
-// indicating missing required fields or type mismatches.
-var ErrorForbidden = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     0,
-		Message:  "Forbidden",
-		Critical: true,
-	},
-})
-
-// ErrorInvalidProtocol contains a pre-serialized message pack-format error
-// indicating missing required fields or type mismatches.
-var ErrorUnauthorized = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     1,
-		Message:  "Unauthorized",
-		Critical: true,
-	},
-})
-
-// ErrorInvalidProtocol contains a pre-serialized message pack-format error
-// indicating missing required fields or type mismatches.
-var ErrorInvalidProtocol = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     3,
-		Message:  "Required fields are missing or their type does not match the declared one",
-		Critical: true,
-	},
-})
-
-// ErrorNoAccount contains a pre-serialized message pack-format error
-// indicating that the account is not linked to any user page.
-var ErrorNoAccount = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     4,
-		Message:  "This account is not linked to any user page",
-		Critical: false,
-	},
-})
-
-// ErrorManyRequest contains a pre-serialized message pack-format error
-// indicating too many requests.
-var ErrorManyRequest = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     5,
-		Message:  "Too many requests",
-		Critical: false,
-	},
-})
-
-// ErrorInvalidFields contains a pre-serialized message pack-format error
-// indicating invalid or missing fields.
-var ErrorInvalidFields = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     6,
-		Message:  "Required fields are missing or their type does not match the declared one",
-		Critical: true,
-	},
-})
-
-// ErrorExpiration contains a pre-serialized message pack-format error
-// indicating token expiration.
-var ErrorExpiration = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     7,
-		Message:  "Token expiration",
-		Critical: true,
-	},
-})
-
-// ErrorServiceWork contains a pre-serialized message pack-format error
-// indicating that technical work is underway.
-var ErrorServiceWork = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     8,
-		Message:  "Technical work is underway",
-		Critical: true,
-	},
-})
-
-// ErrorOutdatedVersion contains a pre-serialized message pack-format error
-// indicating an outdated version of the application.
-var ErrorOutdatedVersion = serializeJson(Data{
-	Error: &ErrorData{
-		Code:     9,
-		Message:  "Outdated version of the application",
-		Critical: true,
-	},
-})
+// CatalogVersion is the version of the error catalog. Codes and types are never
+// renumbered or reused; the version is raised whenever an entry is added or its
+// HTTP status changes.
+const CatalogVersion = 3
+
+// Entry describes an error of the catalog.
+type Entry struct {
+	Code     int    `json:"code"`     // Stable numeric code
+	Type     string `json:"type"`     // Stable machine-readable code
+	Status   int    `json:"status"`   // HTTP status of the response
+	Message  string `json:"message"`  // Default human-readable message
+	Critical bool   `json:"critical"` // Whether the client should not retry as is
+}
+
+// ErrorForbidden indicates that the API key lacks the required scope.
+var ErrorForbidden = Entry{
+	Code:     0,
+	Type:     "forbidden",
+	Status:   403,
+	Message:  "Forbidden",
+	Critical: true,
+}
+
+// ErrorUnauthorized indicates a missing, unknown, expired or revoked API key or signature.
+var ErrorUnauthorized = Entry{
+	Code:     1,
+	Type:     "unauthorized",
+	Status:   401,
+	Message:  "Unauthorized",
+	Critical: true,
+}
+
+// ErrorBadRequest indicates that the request could not be processed, the message explains why.
+var ErrorBadRequest = Entry{
+	Code:     2,
+	Type:     "bad_request",
+	Status:   400,
+	Message:  "Bad request",
+	Critical: true,
+}
+
+// ErrorInvalidProtocol indicates missing required fields or type mismatches.
+var ErrorInvalidProtocol = Entry{
+	Code:     3,
+	Type:     "invalid_protocol",
+	Status:   400,
+	Message:  "Required fields are missing or their type does not match the declared one",
+	Critical: true,
+}
+
+// ErrorNoAccount indicates that the account is not linked to any user page.
+var ErrorNoAccount = Entry{
+	Code:     4,
+	Type:     "no_account",
+	Status:   403,
+	Message:  "This account is not linked to any user page",
+	Critical: false,
+}
+
+// ErrorManyRequest indicates too many requests.
+var ErrorManyRequest = Entry{
+	Code:     5,
+	Type:     "too_many_requests",
+	Status:   429,
+	Message:  "Too many requests",
+	Critical: false,
+}
+
+// ErrorInvalidFields indicates invalid or missing fields, listed in the error details.
+var ErrorInvalidFields = Entry{
+	Code:     6,
+	Type:     "invalid_fields",
+	Status:   422,
+	Message:  "Required fields are missing or their type does not match the declared one",
+	Critical: true,
+}
+
+// ErrorExpiration indicates token expiration.
+var ErrorExpiration = Entry{
+	Code:     7,
+	Type:     "expired",
+	Status:   401,
+	Message:  "Token expiration",
+	Critical: true,
+}
+
+// ErrorServiceWork indicates that technical work is underway.
+var ErrorServiceWork = Entry{
+	Code:     8,
+	Type:     "service_work",
+	Status:   503,
+	Message:  "Technical work is underway",
+	Critical: true,
+}
+
+// ErrorOutdatedVersion indicates an outdated version of the application.
+var ErrorOutdatedVersion = Entry{
+	Code:     9,
+	Type:     "outdated_version",
+	Status:   400,
+	Message:  "Outdated version of the application",
+	Critical: true,
+}
+
+// ErrorScreened indicates that the destination address did not pass screening.
+var ErrorScreened = Entry{
+	Code:     10,
+	Type:     "screened",
+	Status:   422,
+	Message:  "Destination address is not allowed",
+	Critical: true,
+}
+
+// ErrorConflict indicates that the request conflicts with the current state of the resource.
+var ErrorConflict = Entry{
+	Code:     11,
+	Type:     "conflict",
+	Status:   409,
+	Message:  "Conflict with the current state of the resource",
+	Critical: true,
+}
+
+// ErrorNotFound indicates that the requested resource does not exist.
+var ErrorNotFound = Entry{
+	Code:     12,
+	Type:     "not_found",
+	Status:   404,
+	Message:  "Not found",
+	Critical: true,
+}
+
+// ErrorUnavailable indicates that a dependency of the service is temporarily unavailable.
+var ErrorUnavailable = Entry{
+	Code:     13,
+	Type:     "unavailable",
+	Status:   503,
+	Message:  "Service is temporarily unavailable",
+	Critical: false,
+}
+
+// ErrorTooLarge indicates that the request body exceeds the accepted size.
+var ErrorTooLarge = Entry{
+	Code:     14,
+	Type:     "too_large",
+	Status:   413,
+	Message:  "Request body is too large",
+	Critical: true,
+}
+
+// ErrorInternal indicates an unexpected failure of the service, the details are only logged.
+var ErrorInternal = Entry{
+	Code:     15,
+	Type:     "internal",
+	Status:   500,
+	Message:  "Internal error",
+	Critical: false,
+}
+
+// Catalog lists every entry of the error catalog in code order.
+var Catalog = []Entry{
+	ErrorForbidden,
+	ErrorUnauthorized,
+	ErrorBadRequest,
+	ErrorInvalidProtocol,
+	ErrorNoAccount,
+	ErrorManyRequest,
+	ErrorInvalidFields,
+	ErrorExpiration,
+	ErrorServiceWork,
+	ErrorOutdatedVersion,
+	ErrorScreened,
+	ErrorConflict,
+	ErrorNotFound,
+	ErrorUnavailable,
+	ErrorTooLarge,
+	ErrorInternal,
+}
@@ -0,0 +1,47 @@
+package msg
+
+import (
+	"context"
+	"errors"
+
+	"mint/utils/logger"
+	"mint/utils/mysql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Failure answers a request whose processing failed on a dependency, mostly the database,
+// according to the class of err. The error itself is only logged, never sent to the client:
+//   - a duplicate key is a Conflict;
+//   - a SIGNAL raised by a stored procedure is a Conflict with the MESSAGE_TEXT of the
+//     procedure, which is written for clients;
+//   - a timeout, a deadlock, a lock wait timeout, a cache lock not acquired or a lost
+//     connection is Unavailable, the request can be retried;
+//   - anything else is an Internal error.
+func Failure(ctx *gin.Context, err error) {
+	log := logger.FromContext(ctx.Request.Context())
+
+	var sqlErr *mysql.MySQLError
+	switch {
+	case errors.Is(err, mysql.ErrDuplicateKey):
+		log.Warn("request conflicts with stored data", "error", err)
+		Conflict(ctx, "")
+	case errors.Is(err, mysql.ErrSignal) && errors.As(err, &sqlErr):
+		log.Warn("request refused by the database", "error", err)
+		Conflict(ctx, sqlErr.Message)
+	case errors.Is(err, mysql.ErrTimeout),
+		errors.Is(err, mysql.ErrCanceled),
+		errors.Is(err, mysql.ErrDeadlock),
+		errors.Is(err, mysql.ErrLockWaitTimeout),
+		errors.Is(err, mysql.ErrLock),
+		errors.Is(err, mysql.ErrConnectionLost),
+		errors.Is(err, mysql.ErrMySQLNotInitialized),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled):
+		log.Error("dependency unavailable", "error", err)
+		Unavailable(ctx)
+	default:
+		log.Error("request failed", "error", err)
+		Internal(ctx)
+	}
+}
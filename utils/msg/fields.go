@@ -0,0 +1,76 @@
+package msg
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes why a single field of the request was rejected.
+type FieldError struct {
+	Field string `json:"field"`           // Name of the field as sent by the client
+	Rule  string `json:"rule"`            // Validation rule that failed, or "type" for a type mismatch
+	Param string `json:"param,omitempty"` // Parameter of the rule, e.g. "0" for gt=0, or the expected type
+}
+
+func init() {
+	// Report the names clients send instead of the Go struct field names
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(fieldName)
+	}
+}
+
+// fieldName returns the json or form name of a struct field.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if len(name) != 0 {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// Fields converts a gin binding error into field-level details.
+// Errors that do not concern a particular field yield no details.
+func Fields(err error) []FieldError {
+	var fields []FieldError
+
+	var validation validator.ValidationErrors
+	if errors.As(err, &validation) {
+		for _, item := range validation {
+			fields = append(fields, FieldError{
+				Field: fieldPath(item.Namespace()),
+				Rule:  item.Tag(),
+				Param: item.Param(),
+			})
+		}
+		return fields
+	}
+
+	var mismatch *json.UnmarshalTypeError
+	if errors.As(err, &mismatch) && len(mismatch.Field) != 0 {
+		fields = append(fields, FieldError{
+			Field: mismatch.Field,
+			Rule:  "type",
+			Param: mismatch.Type.String(),
+		})
+	}
+
+	return fields
+}
+
+// fieldPath strips the struct name from a validator namespace such as "WithdrawBody.wallet".
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
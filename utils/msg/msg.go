@@ -6,9 +6,11 @@ import (
 )
 
 type ErrorData struct {
-    Code     int    `json:"code"`
-    Message  string `json:"message"`
-    Critical bool   `json:"critical,omitempty"`
+    Code     int          `json:"code"`
+    Type     string       `json:"type,omitempty"`
+    Message  string       `json:"message"`
+    Critical bool         `json:"critical,omitempty"`
+    Fields   []FieldError `json:"fields,omitempty"`
 }
 
 type Data struct {
@@ -19,6 +21,10 @@ type Data struct {
 
 var ContentType = "application/json; charset=utf-8"
 
+// Compat makes every error respond with HTTP 200, as before the error catalog,
+// for clients that only inspect the "error" object of the envelope.
+var Compat = false
+
 func serializeJson(data Data) []byte {
     message, err := jsoniter.Marshal(&data)
     if err != nil {
@@ -33,81 +39,134 @@ func Send(ctx *gin.Context, data any) {
     }))
 }
 
-func CustomError(ctx *gin.Context, code int, data string) {
-    ctx.Data(200, ContentType, serializeJson(Data{
+// Error sends the catalog entry with its HTTP status, or 200 in compatibility mode,
+// replacing the default message when message is not empty, and aborts the request.
+func Error(ctx *gin.Context, entry Entry, message string, fields ...FieldError) {
+    if len(message) == 0 {
+        message = entry.Message
+    }
+
+    status := entry.Status
+    if Compat {
+        status = 200
+    }
+
+    ctx.Data(status, ContentType, serializeJson(Data{
         Error: &ErrorData{
-            Code:     code,
-            Message:  data,
-            Critical: true,
+            Code:     entry.Code,
+            Type:     entry.Type,
+            Message:  message,
+            Critical: entry.Critical,
+            Fields:   fields,
         },
     }))
+    ctx.Abort()
+}
+
+func CustomError(ctx *gin.Context, code int, data string) {
+    entry := ErrorBadRequest
+    entry.Code = code
+    Error(ctx, entry, data)
 }
 
 // BadRequest sends a response with an error message indicating a bad request.
-// It uses the CustomError function to set the error with a specific code and message.
+// It uses the ErrorBadRequest entry with the given message.
 func BadRequest(ctx *gin.Context, data string) {
-    CustomError(ctx, 2, data)
+    Error(ctx, ErrorBadRequest, data)
+}
+
+// Conflict sends a response with an error message indicating a conflict with the current state.
+// It uses the ErrorConflict entry with the given message.
+func Conflict(ctx *gin.Context, data string) {
+    Error(ctx, ErrorConflict, data)
+}
+
+// NotFound sends a response with an error message indicating a missing resource.
+// It uses the ErrorNotFound entry with the given message.
+func NotFound(ctx *gin.Context, data string) {
+    Error(ctx, ErrorNotFound, data)
+}
+
+// Unavailable sends a response with an error message indicating an unavailable dependency.
+// The error is sent as a JSON formatted response using the provided context.
+func Unavailable(ctx *gin.Context) {
+    Error(ctx, ErrorUnavailable, "")
+}
+
+// TooLarge sends a response with an error message indicating a request body over the limit.
+// The error is sent as a JSON formatted response using the provided context.
+func TooLarge(ctx *gin.Context) {
+    Error(ctx, ErrorTooLarge, "")
+}
+
+// Internal sends a response with an error message indicating an unexpected failure of the service.
+// The error is sent as a JSON formatted response using the provided context.
+func Internal(ctx *gin.Context) {
+    Error(ctx, ErrorInternal, "")
 }
 
 // Forbidden sends a response with an error message indicating forbidden access.
 // The error is sent as a JSON formatted response using the provided context.
 func Forbidden(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorForbidden)
-    ctx.Abort()
+    Error(ctx, ErrorForbidden, "")
 }
 
 // Unauthorized sends a response with an error message indicating unauthorized access.
 // The error is sent as a JSON formatted response using the provided context.
 func Unauthorized(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorUnauthorized)
-    ctx.Abort()
+    Error(ctx, ErrorUnauthorized, "")
 }
 
 // InvalidProtocol sends a response with an error message indicating protocol issues.
 // The error is sent as a JSON formatted response using the provided context.
 func InvalidProtocol(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorInvalidProtocol)
-    ctx.Abort()
+    Error(ctx, ErrorInvalidProtocol, "")
 }
 
 // NoAccount sends a response with an error message indicating that the account is not linked.
 // The error is sent as a JSON formatted response using the provided context.
 func NoAccount(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorNoAccount)
-    ctx.Abort()
+    Error(ctx, ErrorNoAccount, "")
 }
 
 // ManyRequest sends a response with an error message indicating too many requests.
 // The error is sent as a JSON formatted response using the provided context.
 func ManyRequest(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorManyRequest)
-    ctx.Abort()
+    Error(ctx, ErrorManyRequest, "")
 }
 
 // InvalidFields sends a response with an error message indicating invalid fields.
 // The error is sent as a JSON formatted response using the provided context.
-func InvalidFields(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorInvalidFields)
-    ctx.Abort()
+func InvalidFields(ctx *gin.Context, fields ...FieldError) {
+    Error(ctx, ErrorInvalidFields, "", fields...)
+}
+
+// Invalid sends a response with an error message indicating invalid fields,
+// detailing every field rejected by the binding error err.
+func Invalid(ctx *gin.Context, err error) {
+    InvalidFields(ctx, Fields(err)...)
 }
 
 // Expiration sends a response with an error message indicating token expiration.
 // The error is sent as a JSON formatted response using the provided context.
 func Expiration(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorExpiration)
-    ctx.Abort()
+    Error(ctx, ErrorExpiration, "")
 }
 
 // ServiceWork sends a response with an error message indicating technical work.
 // The error is sent as a JSON formatted response using the provided context.
 func ServiceWork(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorServiceWork)
-    ctx.Abort()
+    Error(ctx, ErrorServiceWork, "")
 }
 
 // OutdatedVersion sends a response with an error message indicating an outdated version.
 // The error is sent as a JSON formatted response using the provided context.
 func OutdatedVersion(ctx *gin.Context) {
-    ctx.Data(200, ContentType, ErrorOutdatedVersion)
-    ctx.Abort()
-}
\ No newline at end of file
+    Error(ctx, ErrorOutdatedVersion, "")
+}
+
+// Screened sends a response with an error message indicating a blocked destination address.
+// The error is sent as a JSON formatted response using the provided context.
+func Screened(ctx *gin.Context) {
+    Error(ctx, ErrorScreened, "")
+}
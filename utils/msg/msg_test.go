@@ -0,0 +1,124 @@
+package msg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mint/utils/mysql"
+
+	"github.com/gin-gonic/gin"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func respond(t *testing.T, fn func(ctx *gin.Context)) (*httptest.ResponseRecorder, Data) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	fn(ctx)
+
+	var data Data
+	assert.NoError(t, jsoniter.Unmarshal(recorder.Body.Bytes(), &data))
+	return recorder, data
+}
+
+func TestErrorStatus(t *testing.T) {
+	recorder, data := respond(t, Forbidden)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, 0, data.Error.Code)
+	assert.Equal(t, "forbidden", data.Error.Type)
+	assert.Equal(t, "Forbidden", data.Error.Message)
+
+	recorder, data = respond(t, func(ctx *gin.Context) { BadRequest(ctx, "broken") })
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, 2, data.Error.Code)
+	assert.Equal(t, "broken", data.Error.Message)
+
+	recorder, _ = respond(t, ManyRequest)
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	recorder, _ = respond(t, ServiceWork)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestErrorCompat(t *testing.T) {
+	Compat = true
+	defer func() { Compat = false }()
+
+	recorder, data := respond(t, Unauthorized)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 1, data.Error.Code)
+}
+
+func TestInvalid(t *testing.T) {
+	type body struct {
+		Wallet string `json:"wallet" binding:"required"`
+		Amount uint64 `json:"amount" binding:"required,gt=0"`
+	}
+
+	bind := func(payload string) error {
+		gin.SetMode(gin.TestMode)
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		var b body
+		return ctx.ShouldBindJSON(&b)
+	}
+
+	recorder, data := respond(t, func(ctx *gin.Context) { Invalid(ctx, bind(`{"amount":0}`)) })
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Equal(t, "invalid_fields", data.Error.Type)
+	assert.Equal(t, []FieldError{
+		{Field: "wallet", Rule: "required"},
+		{Field: "amount", Rule: "required"},
+	}, data.Error.Fields)
+
+	_, data = respond(t, func(ctx *gin.Context) { Invalid(ctx, bind(`{"wallet":"a","amount":"1"}`)) })
+	assert.Equal(t, []FieldError{{Field: "amount", Rule: "type", Param: "uint64"}}, data.Error.Fields)
+}
+
+func TestCatalog(t *testing.T) {
+	codes := map[int]bool{}
+	types := map[string]bool{}
+	for i, entry := range Catalog {
+		assert.Equal(t, i, entry.Code)
+		assert.False(t, codes[entry.Code])
+		assert.False(t, types[entry.Type])
+		assert.NotZero(t, entry.Status)
+		codes[entry.Code] = true
+		types[entry.Type] = true
+	}
+}
+
+func TestFailure(t *testing.T) {
+	fail := func(err error) (*httptest.ResponseRecorder, Data) {
+		return respond(t, func(ctx *gin.Context) {
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			Failure(ctx, err)
+		})
+	}
+
+	recorder, data := fail(fmt.Errorf("%w: Duplicate entry 'tx' for key 'transaction'", mysql.ErrDuplicateKey))
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.NotContains(t, data.Error.Message, "Duplicate entry")
+
+	// The message of a procedure is meant for the client
+	recorder, data = fail(fmt.Errorf("%w: %w", mysql.ErrSignal, &mysql.MySQLError{Number: 1644, Message: "payout is already claimed"}))
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "payout is already claimed", data.Error.Message)
+
+	recorder, data = fail(fmt.Errorf("%w: i/o timeout", mysql.ErrConnectionLost))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "unavailable", data.Error.Type)
+
+	recorder, _ = fail(fmt.Errorf("%w: %w", mysql.ErrLock, mysql.ErrLockTimeout))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	recorder, data = fail(errors.New("Error 1146: Table 'mint.queue' doesn't exist"))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, ErrorInternal.Message, data.Error.Message)
+}
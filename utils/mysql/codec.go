@@ -0,0 +1,111 @@
+package mysql
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ugorji/go/codec"
+)
+
+// Format identifies the serialization of a cached result. It is stored in the header of
+// every entry, so the values are part of the cache layout and must never change.
+type Format uint8
+
+const (
+	FormatJSON    Format = 1 // JSON, readable by any instance, times keep their offset
+	FormatMsgPack Format = 2 // MessagePack, compact, times are read back in UTC
+	FormatGob     Format = 3 // gob, Go only, times keep their offset
+	FormatCBOR    Format = 4 // CBOR, compact, times are read back in UTC to the microsecond
+)
+
+// Codec serializes the results of queries kept in the cache. Results are decoded with the
+// codec of the format they were written in, so changing the codec of a CoreEntity never
+// misreads the entries written before.
+type Codec interface {
+	// Format identifies the codec in the header of the entries it writes.
+	Format() Format
+
+	// Marshal serializes a result.
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal deserializes a result into the value pointed to by v.
+	Unmarshal(data []byte, v any) error
+}
+
+// Codecs of the supported formats.
+var (
+	JSON        Codec = jsonCodec{}
+	MessagePack Codec = handleCodec{format: FormatMsgPack, handle: &codec.MsgpackHandle{WriteExt: true}}
+	Gob         Codec = gobCodec{}
+	CBOR        Codec = handleCodec{format: FormatCBOR, handle: &codec.CborHandle{}}
+)
+
+// codecs holds the codec of every format, to read entries written by any of them.
+var codecs = map[Format]Codec{
+	FormatJSON:    JSON,
+	FormatMsgPack: MessagePack,
+	FormatGob:     Gob,
+	FormatCBOR:    CBOR,
+}
+
+// CodecByName returns the codec named json, msgpack, gob or cbor.
+func CodecByName(name string) (Codec, error) {
+	switch name {
+	case "json", "":
+		return JSON, nil
+	case "msgpack":
+		return MessagePack, nil
+	case "gob":
+		return Gob, nil
+	case "cbor":
+		return CBOR, nil
+	}
+	return nil, fmt.Errorf("unknown cache codec %q", name)
+}
+
+// jsonCodec serializes results with jsoniter, as the cache always did.
+type jsonCodec struct{}
+
+func (jsonCodec) Format() Format                     { return FormatJSON }
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return jsoniter.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return jsoniter.Unmarshal(data, v) }
+
+// gobCodec serializes results with encoding/gob.
+type gobCodec struct{}
+
+func (gobCodec) Format() Format { return FormatGob }
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// handleCodec serializes results with a handle of github.com/ugorji/go/codec, which reads
+// the json tags of the results like jsoniter does.
+type handleCodec struct {
+	format Format
+	handle codec.Handle // Configured once, then safe for concurrent use
+}
+
+func (h handleCodec) Format() Format { return h.format }
+
+func (h handleCodec) Marshal(v any) ([]byte, error) {
+	var out []byte
+	if err := codec.NewEncoderBytes(&out, h.handle).Encode(v); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (h handleCodec) Unmarshal(data []byte, v any) error {
+	return codec.NewDecoderBytes(data, h.handle).Decode(v)
+}
@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Payout is a result holding a time, whose offset is lost by some codecs.
+type Payout struct {
+	ID        int       `json:"id"`
+	Wallet    string    `json:"wallet"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TestCodecs checks that every codec reads back what it writes.
+func TestCodecs(t *testing.T) {
+	zone := time.FixedZone("MSK", 3*60*60)
+	payout := Payout{ID: 1, Wallet: "EQ...", CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 0, zone)}
+
+	for _, codec := range []Codec{JSON, MessagePack, Gob, CBOR} {
+		data, err := codec.Marshal(&payout)
+		assert.NoError(t, err)
+
+		var got Payout
+		assert.NoError(t, codec.Unmarshal(data, &got))
+		assert.Equal(t, payout.ID, got.ID)
+		assert.Equal(t, payout.Wallet, got.Wallet)
+		assert.True(t, payout.CreatedAt.Equal(got.CreatedAt), "format %d: got %v", codec.Format(), got.CreatedAt)
+
+		// MessagePack and CBOR read times back in UTC
+		if codec == JSON || codec == Gob {
+			_, offset := got.CreatedAt.Zone()
+			assert.Equal(t, 3*60*60, offset, "format %d", codec.Format())
+		}
+	}
+
+	_, err := CodecByName("yaml")
+	assert.Error(t, err)
+}
+
+// TestQueryCodecChange checks that results cached with one codec are read by an entity
+// using another one, as during a rolling upgrade.
+func TestQueryCodecChange(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	cache := NewInMemoryStorage()
+	defer cache.Close()
+
+	entity := func(codec Codec) *CoreEntity {
+		return &CoreEntity{
+			DB:           db,
+			prepare:      make(map[string]*sql.Stmt),
+			CacheEnabled: true,
+			cache:        cache,
+			mutex:        &MockMutex{},
+			codec:        codec,
+		}
+	}
+	read := func(c *CoreEntity) *Payout {
+		result, err := Query(c, Params{
+			Exec:       "PAYOUT_GET",
+			Args:       []any{1},
+			CacheDelay: time.Minute,
+		}, func(rows *sql.Rows) (*Payout, *MySQLError) {
+			var data Payout
+			if rows.Next() {
+				_ = rows.Scan(&data.ID, &data.Wallet, &data.CreatedAt)
+			}
+			return &data, nil
+		})
+		assert.Nil(t, err)
+		return result
+	}
+
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	mock.ExpectPrepare(`CALL PAYOUT_GET\(\?\)`).ExpectQuery().WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "wallet", "created_at"}).AddRow(1, "EQ...", created))
+
+	// The old instance caches with JSON, the new one reads it with CBOR configured
+	assert.Equal(t, "EQ...", read(entity(JSON)).Wallet)
+	assert.Equal(t, "EQ...", read(entity(CBOR)).Wallet)
+
+	// Gob is the default of no instance, its entries are still read by the others
+	cache.Reset()
+	mock.ExpectPrepare(`CALL PAYOUT_GET\(\?\)`).ExpectQuery().WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "wallet", "created_at"}).AddRow(1, "EQ...", created))
+	assert.True(t, created.Equal(read(entity(Gob)).CreatedAt))
+	assert.True(t, created.Equal(read(entity(nil)).CreatedAt))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
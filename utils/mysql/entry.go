@@ -0,0 +1,162 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Layout of a cached result: a header followed by the serialized result.
+//
+//	magic (1) | version (1) | format (1) | flags (1) | fresh until, unix ns (8) | query duration, ns (8) | result
+//
+// JSON never starts with the magic byte, so values cached before the header existed are
+// still read, as JSON results that stay fresh for as long as the storage keeps them.
+const (
+	entryMagic   = 0x00
+	entryVersion = 1
+	entryHeader  = 20
+
+	flagMissing = 1 << 0 // The query found no result
+)
+
+// entry is a result stored in the cache with what is needed to decide when to refresh it.
+type entry struct {
+	format  Format        // The serialization of the payload
+	missing bool          // Whether the query found no result, the payload is then empty
+	fresh   time.Time     // Time until which the result is fresh, zero if it never goes stale
+	delta   time.Duration // Duration of the query that produced the result
+	payload []byte        // The serialized result
+}
+
+// encode returns the bytes stored in the cache for e.
+func (e entry) encode() []byte {
+	data := make([]byte, entryHeader, entryHeader+len(e.payload))
+	data[0] = entryMagic
+	data[1] = entryVersion
+	data[2] = byte(e.format)
+	if e.missing {
+		data[3] |= flagMissing
+	}
+	binary.BigEndian.PutUint64(data[4:], uint64(e.fresh.UnixNano()))
+	binary.BigEndian.PutUint64(data[12:], uint64(e.delta))
+	return append(data, e.payload...)
+}
+
+// decodeEntry reads the bytes stored in the cache. It reports false for a header of an
+// unknown version, which is then treated as a miss.
+func decodeEntry(data []byte) (entry, bool) {
+	if len(data) == 0 || data[0] != entryMagic {
+		return entry{format: FormatJSON, payload: data}, true
+	}
+	if len(data) < entryHeader || data[1] != entryVersion {
+		return entry{}, false
+	}
+	return entry{
+		format:  Format(data[2]),
+		missing: data[3]&flagMissing != 0,
+		fresh:   time.Unix(0, int64(binary.BigEndian.Uint64(data[4:]))),
+		delta:   time.Duration(binary.BigEndian.Uint64(data[12:])),
+		payload: data[entryHeader:],
+	}, true
+}
+
+// expired reports whether the result must be refreshed at now. With a positive beta the
+// result expires early at random, the sooner the longer the query took, so the callers of
+// a popular key do not all miss at the same time ("XFetch").
+func (e entry) expired(now time.Time, beta float64) bool {
+	if e.fresh.IsZero() {
+		return false
+	}
+	if beta > 0 && e.delta > 0 {
+		// 1 - rand.Float64() is in (0, 1], so the logarithm is finite and not positive
+		now = now.Add(time.Duration(-float64(e.delta) * beta * math.Log(1-rand.Float64())))
+	}
+	return !now.Before(e.fresh)
+}
+
+// lookup retrieves the entry cached under key.
+func lookup(c *CoreEntity, key string) (entry, bool) {
+	data, err := c.cache.Get(key)
+	if err != nil || data == nil {
+		return entry{}, false
+	}
+	return decodeEntry(data)
+}
+
+// keep caches the serialized result of a query that took delta, unless its tags were
+// invalidated since gen. A result not found is only cached with a positive NegativeDelay,
+// and for that long.
+func (c *CoreEntity) keep(key string, payload []byte, missing bool, delta time.Duration, params Params, gen int64) error {
+	delay := params.CacheDelay
+	if missing {
+		if params.NegativeDelay <= 0 {
+			return nil
+		}
+		delay, payload = params.NegativeDelay, nil
+	}
+
+	e := entry{
+		format:  c.codecOf().Format(),
+		missing: missing,
+		fresh:   time.Now().Add(delay),
+		delta:   delta,
+		payload: payload,
+	}
+	// The storage keeps the result while it may be served stale
+	return c.store(key, e.encode(), delay+params.StaleDelay, params.Tags, gen)
+}
+
+// refresh queries a stale result again in the background while callers are served the
+// stale one. A single refresh per key runs in this process, and the mutex guarding the
+// cache keeps other instances from running theirs at the same time. The callback runs
+// after the caller has returned, see Params.StaleDelay.
+func refresh[T any](
+	ctx context.Context,
+	c *CoreEntity,
+	params Params,
+	key string,
+	stale entry,
+	callback func(rows *sql.Rows) (*T, *MySQLError),
+) {
+	if _, busy := c.refreshing.LoadOrStore(key, struct{}{}); busy {
+		return
+	}
+
+	// The refresh outlives the caller, but stays in its trace
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer c.refreshing.Delete(key)
+
+		label := labelOf(params)
+		ctx, span := startSpan(ctx, "mysql refresh "+label,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(keyExec.String(label)),
+		)
+		defer span.End()
+
+		mutexKey := mutexKeyOf(key)
+		token, lockErr := c.mutex.Lock(mutexKey)
+		if lockErr != nil {
+			return
+		}
+		defer c.mutex.Unlock(mutexKey, token)
+
+		// Another instance may have refreshed the result while this one waited for the lock
+		if e, ok := lookup(c, key); ok && !e.fresh.Equal(stale.fresh) && !e.expired(time.Now(), 0) {
+			return
+		}
+
+		start := time.Now()
+		_, err := fetch(ctx, c, params, key, callback)
+		c.observe(label, start, err)
+		if err != nil {
+			fail(span, err)
+		}
+	}()
+}
@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEntry checks the header of cached results and their expiration.
+func TestEntry(t *testing.T) {
+	t.Run("Encode and Decode", func(t *testing.T) {
+		fresh := time.Unix(0, time.Now().UnixNano())
+		e := entry{format: FormatCBOR, fresh: fresh, delta: time.Second, payload: []byte{0xa0}}
+
+		got, ok := decodeEntry(e.encode())
+		assert.True(t, ok)
+		assert.Equal(t, e, got)
+
+		got, ok = decodeEntry(entry{format: FormatJSON, missing: true, fresh: fresh}.encode())
+		assert.True(t, ok)
+		assert.True(t, got.missing)
+		assert.Empty(t, got.payload)
+	})
+
+	t.Run("Legacy Value", func(t *testing.T) {
+		// A value cached without a header is a result that never goes stale
+		got, ok := decodeEntry([]byte(`{"id":1}`))
+		assert.True(t, ok)
+		assert.Equal(t, []byte(`{"id":1}`), got.payload)
+		assert.Equal(t, FormatJSON, got.format)
+		assert.False(t, got.expired(time.Now(), 1))
+	})
+
+	t.Run("Unknown Version", func(t *testing.T) {
+		data := entry{fresh: time.Now()}.encode()
+		data[1] = entryVersion + 1
+
+		_, ok := decodeEntry(data)
+		assert.False(t, ok)
+	})
+
+	t.Run("Early Expiry", func(t *testing.T) {
+		now := time.Now()
+		e := entry{fresh: now.Add(time.Second), delta: time.Hour}
+
+		assert.False(t, e.expired(now, 0))
+		assert.True(t, e.expired(now.Add(time.Second), 0))
+
+		// A query slower than the remaining lifetime almost always expires early
+		early := 0
+		for i := 0; i < 100; i++ {
+			if e.expired(now, 1) {
+				early++
+			}
+		}
+		assert.Greater(t, early, 90)
+	})
+}
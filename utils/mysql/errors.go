@@ -1,9 +1,132 @@
 package mysql
 
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Classes of errors returned by Query, Exec and transactions. Every MySQLError built by
+// this package wraps its class, so callers test it with errors.Is:
+//
+//	if errors.Is(err, mysql.ErrDuplicateKey) { ... }
+//
+// errors.As with *mysql.MySQLError gives access to the number and the message.
+var (
+	ErrTimeout         = errors.New("mysql: timeout")           // The timeout of the query or the transaction expired
+	ErrCanceled        = errors.New("mysql: canceled")          // The context of the query was canceled
+	ErrDeadlock        = errors.New("mysql: deadlock")          // The server rolled back the transaction to resolve a deadlock (1213)
+	ErrLockWaitTimeout = errors.New("mysql: lock wait timeout") // A row lock was not acquired in time (1205)
+	ErrDuplicateKey    = errors.New("mysql: duplicate key")     // A unique key already holds the value (1062)
+	ErrConnectionLost  = errors.New("mysql: connection lost")   // The connection to the server broke
+	ErrSignal          = errors.New("mysql: signal")            // A stored procedure raised SIGNAL SQLSTATE '45000', the message is its MESSAGE_TEXT
+	ErrSerialize       = errors.New("mysql: serialize")         // The result could not be serialized for the cache
+	ErrLock            = errors.New("mysql: lock")              // The lock guarding a cached result was not acquired, the cause tells why
+)
+
+// Numbers of the server errors that are classified.
+const (
+	numberDuplicateKey    = 1062 // ER_DUP_ENTRY
+	numberLockWaitTimeout = 1205 // ER_LOCK_WAIT_TIMEOUT
+	numberDeadlock        = 1213 // ER_LOCK_DEADLOCK
+	numberSignal          = 1644 // ER_SIGNAL_EXCEPTION
+	numberServerGone      = 2006 // CR_SERVER_GONE_ERROR
+	numberServerLost      = 2013 // CR_SERVER_LOST
+)
+
+// stateSignal is the SQLSTATE of an unhandled user-defined exception.
+var stateSignal = [5]byte{'4', '5', '0', '0', '0'}
+
 func NewError(err error) *MySQLError {
 	return &MySQLError{
 		Number:   45000,
 		SQLState: [5]byte{0, 0, 0, 0, 0},
 		Message:  err.Error(),
+		cause:    err,
+	}
+}
+
+// convertError converts an error of the driver into a MySQLError wrapping its class.
+// Deadlocks and timeouts keep the custom 45000 errors Query always returned for them.
+func convertError(err error) *MySQLError {
+	var sqlErr *mysql.MySQLError
+	if errors.As(err, &sqlErr) {
+		res := &MySQLError{
+			Number:   sqlErr.Number,
+			SQLState: sqlErr.SQLState,
+			Message:  sqlErr.Message,
+			cause:    err,
+		}
+
+		switch {
+		case sqlErr.Number == numberDeadlock:
+			res.Number, res.SQLState, res.Message = 45000, [5]byte{}, "DEADLOCK" // Custom error for deadlock
+			res.kind = ErrDeadlock
+		case sqlErr.Number == numberLockWaitTimeout:
+			res.kind = ErrLockWaitTimeout
+		case sqlErr.Number == numberDuplicateKey:
+			res.kind = ErrDuplicateKey
+		case sqlErr.Number == numberSignal || sqlErr.SQLState == stateSignal:
+			res.kind = ErrSignal
+		case sqlErr.Number == numberServerGone || sqlErr.Number == numberServerLost:
+			res.kind = ErrConnectionLost
+		}
+		return res
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &MySQLError{
+			Number:  45000,
+			Message: "TIMEOUT", // Custom error for query timeout
+			kind:    ErrTimeout,
+			cause:   err,
+		}
+	case errors.Is(err, context.Canceled):
+		return &MySQLError{
+			Number:  45000,
+			Message: "CANCELED",
+			kind:    ErrCanceled,
+			cause:   err,
+		}
+	case lostConnection(err):
+		res := NewError(err)
+		res.kind = ErrConnectionLost
+		return res
+	}
+	return NewError(err)
+}
+
+// lockError returns the error of a query whose cache lock was not acquired, wrapping the
+// error of the mutex, such as ErrLockTimeout.
+func lockError(err error) *MySQLError {
+	return &MySQLError{
+		Number:  45000,
+		Message: "LOCK",
+		kind:    ErrLock,
+		cause:   err,
 	}
 }
+
+// convertContextError is convertError for a statement run under ctx. Drivers do not always
+// return the error of ctx once it is done, so it is added to the chain.
+func convertContextError(ctx context.Context, err error) *MySQLError {
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		err = fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	return convertError(err)
+}
+
+// lostConnection reports whether err means the connection to the server broke.
+func lostConnection(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr)
+}
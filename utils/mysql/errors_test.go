@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"Deadlock", &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, ErrDeadlock},
+		{"Lock Wait Timeout", &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, ErrLockWaitTimeout},
+		{"Duplicate Key", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, ErrDuplicateKey},
+		{"Signal", &mysql.MySQLError{Number: 1644, SQLState: [5]byte{'4', '5', '0', '0', '0'}, Message: "INSUFFICIENT_FUNDS"}, ErrSignal},
+		{"Server Gone", &mysql.MySQLError{Number: 2006, Message: "MySQL server has gone away"}, ErrConnectionLost},
+		{"Bad Connection", driver.ErrBadConn, ErrConnectionLost},
+		{"Invalid Connection", fmt.Errorf("read: %w", mysql.ErrInvalidConn), ErrConnectionLost},
+		{"Timeout", context.DeadlineExceeded, ErrTimeout},
+		{"Canceled", context.Canceled, ErrCanceled},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := convertError(c.err)
+			assert.ErrorIs(t, err, c.kind)
+			assert.ErrorIs(t, err, c.err)
+
+			var sqlErr *MySQLError
+			assert.True(t, errors.As(error(err), &sqlErr))
+		})
+	}
+
+	// The MESSAGE_TEXT of a signal is kept
+	err := convertError(&mysql.MySQLError{Number: 1644, SQLState: [5]byte{'4', '5', '0', '0', '0'}, Message: "INSUFFICIENT_FUNDS"})
+	assert.Equal(t, "INSUFFICIENT_FUNDS", err.Message)
+
+	// Unknown errors are wrapped without a class
+	cause := errors.New("unknown")
+	err = convertError(cause)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrConnectionLost)
+	assert.Equal(t, "unknown", err.Message)
+}
+
+// TestSentinels checks that the error of every class matches its sentinel and no other one.
+func TestSentinels(t *testing.T) {
+	sentinels := []error{
+		ErrTimeout, ErrCanceled, ErrDeadlock, ErrLockWaitTimeout, ErrDuplicateKey,
+		ErrConnectionLost, ErrSignal, ErrSerialize, ErrMySQLNotInitialized,
+	}
+	errs := map[error]*MySQLError{
+		ErrTimeout:             convertError(context.DeadlineExceeded),
+		ErrCanceled:            convertError(context.Canceled),
+		ErrDeadlock:            convertError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}),
+		ErrLockWaitTimeout:     convertError(&mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}),
+		ErrDuplicateKey:        convertError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}),
+		ErrConnectionLost:      convertError(driver.ErrBadConn),
+		ErrSignal:              convertError(&mysql.MySQLError{Number: 1644, SQLState: [5]byte{'4', '5', '0', '0', '0'}, Message: "INSUFFICIENT_FUNDS"}),
+		ErrSerialize:           {Number: 45000, Message: "SERIALIZE", kind: ErrSerialize},
+		ErrMySQLNotInitialized: ErrMySQLNotInitialized,
+	}
+
+	for _, sentinel := range sentinels {
+		err := errs[sentinel]
+		for _, other := range sentinels {
+			if other == sentinel {
+				assert.ErrorIs(t, err, other, err.Message)
+			} else {
+				assert.NotErrorIs(t, err, other, "%v matches %v", err, other)
+			}
+		}
+	}
+
+	// Errors built by NewError share the number of the sentinel but not its message
+	assert.NotErrorIs(t, NewError(errors.New("boom")), ErrMySQLNotInitialized)
+}
+
+// TestQueryErrors checks that failures of the driver never panic and keep their class.
+func TestQueryErrors(t *testing.T) {
+	// Test case: A lost connection while querying
+	t.Run("Connection Lost", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		query := "SELECT id, name FROM users WHERE id = ?"
+		mock.ExpectPrepare(query).ExpectQuery().WithArgs(1).WillReturnError(mysql.ErrInvalidConn)
+
+		result, err := Query(c, Params{Query: query, Args: []any{1}}, func(rows *sql.Rows) (*User, *MySQLError) {
+			return nil, nil
+		})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrConnectionLost)
+	})
+
+	// Test case: A failure to prepare keeps the error of the server
+	t.Run("Prepare", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectPrepare(`CALL USER_GET\(\?\)`).WillReturnError(&mysql.MySQLError{Number: 1305, Message: "PROCEDURE USER_GET does not exist"})
+
+		_, err := Query(c, Params{Exec: "USER_GET", Args: []any{1}}, func(rows *sql.Rows) (*User, *MySQLError) {
+			return nil, nil
+		})
+
+		assert.Equal(t, uint16(1305), err.Number)
+		assert.Equal(t, "PROCEDURE USER_GET does not exist", err.Message)
+	})
+}
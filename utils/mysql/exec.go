@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Result describes the outcome of a statement executed by Exec.
+type Result struct {
+	RowsAffected int64 // Number of rows changed by the statement
+	LastInsertID int64 // Auto-increment id generated by the last insert, 0 if none
+}
+
+// Exec executes a statement that returns no rows, such as a stored procedure that only
+// writes, and reports the affected rows and the last insert id. Results are never cached.
+func Exec(c *CoreEntity, params Params) (*Result, *MySQLError) {
+	return ExecContext(context.Background(), c, params)
+}
+
+// ExecContext is Exec bounded by ctx: the statement is canceled with ctx, and params.Timeout
+// still applies as an upper bound.
+func ExecContext(ctx context.Context, c *CoreEntity, params Params) (*Result, *MySQLError) {
+	label := labelOf(params)
+
+	ctx, span := startSpan(ctx, "mysql "+label,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			keyExec.String(label),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	res, err := exec(ctx, c, params)
+	c.observe(label, start, err)
+	if err != nil {
+		fail(span, err)
+		return nil, err
+	}
+
+	// Evict the cached results this statement made stale
+	c.evict(ctx, params.Invalidate)
+	return res, nil
+}
+
+// exec implements Exec without instrumentation.
+func exec(parent context.Context, c *CoreEntity, params Params) (*Result, *MySQLError) {
+	// Create a context with a timeout for the statement execution
+	ctx, cancel := createContextWithTimeout(parent, params.Timeout)
+	defer cancel()
+
+	// Calls with OUT parameters run on a dedicated connection
+	if params.Query == "" && hasOut(params.Args) {
+		return execOut(ctx, c, params)
+	}
+
+	// Retrieve the prepared statement
+	prepare, err := c.getPreparedStatement(statement(params))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	res, err := prepare.ExecContext(ctx, params.Args...)
+	if err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+	return newResult(res), nil
+}
+
+// newResult reads the outcome of a statement. The driver always knows both values,
+// so the errors of sql.Result are ignored.
+func newResult(res sql.Result) *Result {
+	affected, _ := res.RowsAffected()
+	id, _ := res.LastInsertId()
+	return &Result{
+		RowsAffected: affected,
+		LastInsertID: id,
+	}
+}
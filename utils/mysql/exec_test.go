@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"mint/utils/logger"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExec contains multiple test cases for the Exec function.
+func TestExec(t *testing.T) {
+	// Test case: The outcome of the statement is reported and the statement stays prepared
+	t.Run("Affected Rows and Last Insert ID", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		prepare := mock.ExpectPrepare(`CALL USER_ADD\(\?, \?\)`)
+		prepare.ExpectExec().WithArgs(1, "John Doe").WillReturnResult(sqlmock.NewResult(7, 1))
+		prepare.ExpectExec().WithArgs(2, "Jane Doe").WillReturnResult(sqlmock.NewResult(8, 1))
+
+		result, err := Exec(c, Params{Exec: "USER_ADD", Args: []any{1, "John Doe"}, Timeout: 5 * time.Second})
+		assert.Nil(t, err)
+		assert.Equal(t, Result{RowsAffected: 1, LastInsertID: 7}, *result)
+
+		result, err = Exec(c, Params{Exec: "USER_ADD", Args: []any{2, "Jane Doe"}, Timeout: 5 * time.Second})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(8), result.LastInsertID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Test case: A statement that changes nothing reports no affected rows
+	t.Run("No Affected Rows", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectPrepare(`CALL USER_DELETE\(\?\)`).ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		result, err := Exec(c, Params{Exec: "USER_DELETE", Args: []any{1}})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), result.RowsAffected)
+	})
+
+	// Test case: Errors are mapped like in Query
+	t.Run("Deadlock", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectPrepare(`CALL USER_DELETE\(\?\)`).ExpectExec().WithArgs(1).WillReturnError(&mysql.MySQLError{
+			Number:  1213,
+			Message: "Deadlock found",
+		})
+
+		result, err := Exec(c, Params{Exec: "USER_DELETE", Args: []any{1}})
+		assert.Nil(t, result)
+		assert.Equal(t, uint16(45000), err.Number)
+		assert.Equal(t, "DEADLOCK", err.Message)
+	})
+
+	// Test case: A failed invalidation is logged, the statement still succeeded
+	t.Run("Invalidation Failure", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		cache := failingStorage{NewInMemoryStorage()}
+		defer cache.Close()
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt), CacheEnabled: true, cache: cache}
+
+		mock.ExpectPrepare(`CALL USER_DELETE\(\?\)`).ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		var out bytes.Buffer
+		ctx := logger.WithContext(context.Background(), slog.New(slog.NewTextHandler(&out, nil)))
+
+		result, err := ExecContext(ctx, c, Params{Exec: "USER_DELETE", Args: []any{1}, Invalidate: []string{"users"}})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), result.RowsAffected)
+		assert.Contains(t, out.String(), "failed to invalidate cached results")
+		assert.Contains(t, out.String(), "unreachable")
+	})
+}
+
+// failingStorage is a TaggedStorage whose invalidations always fail.
+type failingStorage struct {
+	*InMemoryStorage
+}
+
+func (failingStorage) Invalidate(...string) error { return errors.New("unreachable") }
+
+// TestExecContext checks that the timeout of the statement bounds the caller's context.
+func TestExecContext(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+	mock.ExpectPrepare(`CALL USER_DELETE\(\?\)`).ExpectExec().WithArgs(1).
+		WillDelayFor(time.Second).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := ExecContext(context.Background(), c, Params{Exec: "USER_DELETE", Args: []any{1}, Timeout: 10 * time.Millisecond})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrTimeout)
+}
@@ -1,116 +1,339 @@
 package mysql
 
 import (
+	"container/list"
 	"errors"
 	"sync"
 	"time"
 )
 
 // InMemoryStorage provides a thread-safe in-memory cache implementation.
-// It stores key-value pairs with an expiration timestamp.
+// It stores key-value pairs with an expiration timestamp, bounded by the number of
+// entries and their size: once a bound is exceeded, the least recently used entries
+// are evicted. Expired entries are removed by a janitor running until Close.
 type InMemoryStorage struct {
-	cache map[string]CacheEntry // The in-memory cache where data is stored.
-	mu    sync.RWMutex          // A read-write mutex to ensure thread-safe access to the cache.
+	cache   map[string]*list.Element       // The entries of the cache, by key.
+	lru     *list.List                     // The entries from the most to the least recently used.
+	tags    map[string]map[string]struct{} // The keys of every tag.
+	keyTags map[string][]string            // The tags of every tagged key.
+	gens    map[string]int64               // The number of invalidations of every tag, kept across Reset.
+	resets  int64                          // The number of resets, added to the generation of every tag.
+	mu      sync.Mutex                     // A mutex to ensure thread-safe access to the cache, reads update the LRU order.
+
+	opt   InMemoryOptions // The bounds of the cache.
+	bytes int64           // The size of every entry in the cache.
+	stats InMemoryStats   // The counters of the cache, entries and bytes are filled in by Stats.
+
+	stop chan struct{} // Closed by Close to stop the janitor.
+	once sync.Once     // Guards the close of stop.
+}
+
+// InMemoryOptions bounds an InMemoryStorage. A zero bound means no limit.
+type InMemoryOptions struct {
+	MaxEntries int           // Maximum number of entries.
+	MaxBytes   int64         // Maximum size of the keys and values of every entry.
+	Cleanup    time.Duration // Interval between two removals of expired entries, one minute if 0.
+}
+
+// InMemoryStats reports the activity of an InMemoryStorage.
+type InMemoryStats struct {
+	Hits        uint64 // Lookups that found a valid entry.
+	Misses      uint64 // Lookups of missing or expired keys.
+	Evictions   uint64 // Entries evicted to stay within the bounds.
+	Expirations uint64 // Expired entries removed by lookups or the janitor.
+	Entries     int    // Number of entries in the cache.
+	Bytes       int64  // Size of the keys and values of every entry.
 }
 
 // CacheEntry represents a single entry in the cache.
 // It holds the value and its expiration timestamp.
 type CacheEntry struct {
 	Value     []byte    // The cached value.
-	Timestamp time.Time // The expiration time of the entry.
+	Timestamp time.Time // The expiration time of the entry, zero if it never expires.
+}
+
+// memoryEntry is the element of the LRU list holding a CacheEntry.
+type memoryEntry struct {
+	CacheEntry
+	key  string // The key of the entry, to remove it from the map once evicted.
+	size int64  // The size of the key and the value.
+}
+
+// expired reports whether the entry is expired at now.
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.Timestamp.IsZero() && now.After(e.Timestamp)
 }
 
-// NewInMemoryStorage creates and initializes a new InMemoryStorage instance.
-// It also starts a cleanup process to remove expired entries after one minute.
+// NewInMemoryStorage creates and initializes a new unbounded InMemoryStorage instance.
+// Its janitor removes expired entries every minute until Close.
 func NewInMemoryStorage() *InMemoryStorage {
+	return NewBoundedInMemoryStorage(InMemoryOptions{})
+}
+
+// NewBoundedInMemoryStorage creates an InMemoryStorage within the bounds of opt and starts its janitor.
+func NewBoundedInMemoryStorage(opt InMemoryOptions) *InMemoryStorage {
+	if opt.Cleanup <= 0 {
+		opt.Cleanup = time.Minute
+	}
+
 	st := &InMemoryStorage{
-		cache: make(map[string]CacheEntry), // Initialize the cache map.
+		cache:   make(map[string]*list.Element), // Initialize the cache map.
+		lru:     list.New(),
+		tags:    make(map[string]map[string]struct{}),
+		keyTags: make(map[string][]string),
+		gens:    make(map[string]int64),
+		opt:     opt,
+		stop:    make(chan struct{}),
 	}
 
-	// Launch a cleanup goroutine to remove expired entries periodically.
-	go func() {
-		time.Sleep(1 * time.Minute) // Wait for one minute before starting the cleanup.
-		st.cleanUp()
-	}()
+	// Launch the janitor to remove expired entries periodically.
+	go st.janitor()
 
 	return st
 }
 
+// janitor removes expired entries at every tick until the storage is closed.
+func (i *InMemoryStorage) janitor() {
+	ticker := time.NewTicker(i.opt.Cleanup)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-i.stop:
+			return
+		case <-ticker.C:
+			i.cleanUp()
+		}
+	}
+}
+
 // Get retrieves the value associated with the given key from the cache.
 // It returns an error if the key does not exist or has expired.
 func (i *InMemoryStorage) Get(key string) ([]byte, error) {
-	i.mu.RLock() // Acquire a read lock to safely read from the cache.
-	defer i.mu.RUnlock()
+	i.mu.Lock() // Acquire the lock, an expired entry is removed and a valid one moved to the front.
+	defer i.mu.Unlock()
 
-	entry, ok := i.cache[key]
+	elem, ok := i.cache[key]
 	if !ok {
 		// Key not found in the cache.
+		i.stats.Misses++
 		return nil, errors.New("key not found")
 	}
 
-	if time.Now().After(entry.Timestamp) {
+	entry := elem.Value.(*memoryEntry)
+	if entry.expired(time.Now()) {
 		// Key has expired. Remove it from the cache and return an error.
-		delete(i.cache, key)
+		i.deleteLocked(key)
+		i.stats.Misses++
+		i.stats.Expirations++
 		return nil, errors.New("key is expired")
 	}
 
 	// Return the cached value if it is still valid.
+	i.lru.MoveToFront(elem)
+	i.stats.Hits++
 	return entry.Value, nil
 }
 
 // Set stores a key-value pair in the cache with a specified expiration duration.
+// A duration of 0 means no expiration.
 func (i *InMemoryStorage) Set(key string, val []byte, exp time.Duration) error {
 	i.mu.Lock() // Acquire a write lock to safely modify the cache.
 	defer i.mu.Unlock()
 
-	// Create or update the cache entry.
-	i.cache[key] = CacheEntry{
-		Value:     val,
-		Timestamp: time.Now().Add(exp), // Set the expiration time.
+	i.setLocked(key, val, exp)
+	return nil
+}
+
+// SetNX stores a key-value pair like Set only if the key does not exist or has expired,
+// and reports whether it was stored.
+func (i *InMemoryStorage) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	i.mu.Lock() // Acquire a write lock, the check and the store are a single step.
+	defer i.mu.Unlock()
+
+	if elem, ok := i.cache[key]; ok && !elem.Value.(*memoryEntry).expired(time.Now()) {
+		return false, nil
 	}
+	return i.setLocked(key, val, exp), nil
+}
+
+// SetTagged stores a key-value pair like Set and adds the key to every given tag, unless
+// one of the tags was invalidated since Generation returned gen for them.
+func (i *InMemoryStorage) SetTagged(key string, val []byte, exp time.Duration, tags []string, gen int64) error {
+	i.mu.Lock() // Acquire a write lock to safely modify the cache.
+	defer i.mu.Unlock()
+
+	if i.generationLocked(tags) != gen || !i.setLocked(key, val, exp) {
+		return nil
+	}
+	for _, tag := range tags {
+		if i.tags[tag] == nil {
+			i.tags[tag] = make(map[string]struct{})
+		}
+		i.tags[tag][key] = struct{}{}
+	}
+	i.keyTags[key] = tags
 
 	return nil
 }
 
+// Invalidate removes every key of the given tags, and the tags themselves.
+// It holds the write lock throughout, so no reader sees a partial eviction.
+func (i *InMemoryStorage) Invalidate(tags ...string) error {
+	i.mu.Lock() // Acquire a write lock to safely modify the cache.
+	defer i.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range i.tags[tag] {
+			i.deleteLocked(key)
+		}
+		delete(i.tags, tag)
+		i.gens[tag]++
+	}
+
+	return nil
+}
+
+// Generation returns a counter that grows every time one of the given tags is invalidated,
+// or the cache is reset.
+func (i *InMemoryStorage) Generation(tags ...string) (int64, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.generationLocked(tags), nil
+}
+
+// generationLocked implements Generation.
+// The caller must hold the write lock.
+func (i *InMemoryStorage) generationLocked(tags []string) int64 {
+	gen := i.resets
+	for _, tag := range tags {
+		gen += i.gens[tag]
+	}
+	return gen
+}
+
+// setLocked creates or updates the cache entry, dropping the tags of a previous value,
+// then evicts the least recently used entries until the cache is within its bounds.
+// A value larger than MaxBytes is not stored, setLocked then reports false.
+// The caller must hold the write lock.
+func (i *InMemoryStorage) setLocked(key string, val []byte, exp time.Duration) bool {
+	i.deleteLocked(key)
+
+	size := int64(len(key) + len(val))
+	if i.opt.MaxBytes > 0 && size > i.opt.MaxBytes {
+		return false
+	}
+
+	entry := &memoryEntry{
+		CacheEntry: CacheEntry{Value: val},
+		key:        key,
+		size:       size,
+	}
+	if exp > 0 {
+		entry.Timestamp = time.Now().Add(exp) // Set the expiration time.
+	}
+	i.cache[key] = i.lru.PushFront(entry)
+	i.bytes += size
+
+	for i.overflows() {
+		i.deleteLocked(i.lru.Back().Value.(*memoryEntry).key)
+		i.stats.Evictions++
+	}
+	return true
+}
+
+// overflows reports whether the cache exceeds one of its bounds.
+// The caller must hold the write lock.
+func (i *InMemoryStorage) overflows() bool {
+	return (i.opt.MaxEntries > 0 && i.lru.Len() > i.opt.MaxEntries) ||
+		(i.opt.MaxBytes > 0 && i.bytes > i.opt.MaxBytes)
+}
+
+// deleteLocked removes the key from the cache and from its tags.
+// The caller must hold the write lock.
+func (i *InMemoryStorage) deleteLocked(key string) {
+	i.untagLocked(key)
+
+	elem, ok := i.cache[key]
+	if !ok {
+		return
+	}
+	i.bytes -= elem.Value.(*memoryEntry).size
+	i.lru.Remove(elem)
+	delete(i.cache, key)
+}
+
+// untagLocked removes the key from every tag it belongs to.
+// The caller must hold the write lock.
+func (i *InMemoryStorage) untagLocked(key string) {
+	for _, tag := range i.keyTags[key] {
+		delete(i.tags[tag], key)
+		if len(i.tags[tag]) == 0 {
+			delete(i.tags, tag)
+		}
+	}
+	delete(i.keyTags, key)
+}
+
 // Delete removes a key-value pair from the cache by its key.
 func (i *InMemoryStorage) Delete(key string) error {
 	i.mu.Lock() // Acquire a write lock to safely modify the cache.
 	defer i.mu.Unlock()
 
 	// Remove the key from the cache.
-	delete(i.cache, key)
+	i.deleteLocked(key)
 
 	return nil
 }
 
 // Reset clears all entries from the cache, effectively resetting it to an empty state.
+// The stats are kept.
 func (i *InMemoryStorage) Reset() error {
 	i.mu.Lock() // Acquire a write lock to safely modify the cache.
 	defer i.mu.Unlock()
 
-	// Replace the existing cache map with a new empty map.
-	i.cache = make(map[string]CacheEntry)
+	// Replace the existing cache with a new empty one.
+	i.cache = make(map[string]*list.Element)
+	i.lru.Init()
+	i.tags = make(map[string]map[string]struct{})
+	i.keyTags = make(map[string][]string)
+	i.bytes = 0
+	i.resets++
 
 	return nil
 }
 
-// Close is a placeholder for any cleanup operations when the storage is closed.
-// Currently, it does nothing but exists for extensibility.
+// Stats returns the counters of the cache along with its current number of entries and size.
+func (i *InMemoryStorage) Stats() InMemoryStats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	stats := i.stats
+	stats.Entries = i.lru.Len()
+	stats.Bytes = i.bytes
+	return stats
+}
+
+// Close stops the janitor. The entries are kept, so the storage remains usable
+// but expired entries are then only removed when read.
 func (i *InMemoryStorage) Close() error {
+	i.once.Do(func() { close(i.stop) })
 	return nil
 }
 
 // cleanUp removes all expired entries from the cache.
-// It is typically run periodically to free up space in the cache.
+// It is run periodically by the janitor to free up space in the cache.
 func (i *InMemoryStorage) cleanUp() {
 	i.mu.Lock() // Acquire a write lock to safely modify the cache.
 	defer i.mu.Unlock()
 
 	now := time.Now()
-	for key, entry := range i.cache {
+	for key, elem := range i.cache {
 		// If the entry has expired, remove it from the cache.
-		if now.After(entry.Timestamp) {
-			delete(i.cache, key)
+		if elem.Value.(*memoryEntry).expired(now) {
+			i.deleteLocked(key)
+			i.stats.Expirations++
 		}
 	}
 }
@@ -47,6 +47,22 @@ func TestInMemoryStorage(t *testing.T) {
 		}
 	})
 
+	// Test case for storing a key only if it is absent or expired.
+	t.Run("SetNX", func(t *testing.T) {
+		if stored, err := storage.SetNX("nonce", []byte{1}, 50*time.Millisecond); err != nil || !stored {
+			t.Fatalf("expected the absent key to be stored, got %v, %v", stored, err)
+		}
+		if stored, _ := storage.SetNX("nonce", []byte{2}, time.Second); stored {
+			t.Fatalf("expected the present key to be kept")
+		}
+
+		// Wait for the key to expire, it can then be stored again.
+		time.Sleep(100 * time.Millisecond)
+		if stored, _ := storage.SetNX("nonce", []byte{3}, time.Second); !stored {
+			t.Fatalf("expected the expired key to be stored")
+		}
+	})
+
 	// Test case for deleting a key and ensuring it is no longer accessible.
 	t.Run("Delete", func(t *testing.T) {
 		// Set a value with a long expiration.
@@ -190,3 +206,167 @@ func BenchmarkInMemoryStorage(b *testing.B) {
 		}
 	})
 }
+
+// TestInMemoryStorageTags checks the eviction of tagged keys.
+func TestInMemoryStorageTags(t *testing.T) {
+	storage := NewInMemoryStorage()
+
+	_ = storage.SetTagged("stats", []byte{1}, time.Minute, []string{"queue"}, 0)
+	_ = storage.SetTagged("payouts", []byte{2}, time.Minute, []string{"queue", "success"}, 0)
+	_ = storage.SetTagged("keys", []byte{3}, time.Minute, []string{"keys"}, 0)
+
+	if err := storage.Invalidate("queue"); err != nil {
+		t.Fatalf("failed to invalidate: %v", err)
+	}
+	for _, key := range []string{"stats", "payouts"} {
+		if _, err := storage.Get(key); err == nil {
+			t.Errorf("expected %s to be evicted", key)
+		}
+	}
+	if _, err := storage.Get("keys"); err != nil {
+		t.Errorf("expected keys to be kept, got %v", err)
+	}
+
+	// The evicted key no longer belongs to its other tags
+	if len(storage.tags["success"]) != 0 {
+		t.Errorf("expected success to be empty, got %v", storage.tags["success"])
+	}
+
+	// Overwriting a key drops its previous tags
+	_ = storage.Set("keys", []byte{4}, time.Minute)
+	if err := storage.Invalidate("keys"); err != nil {
+		t.Fatalf("failed to invalidate: %v", err)
+	}
+	if _, err := storage.Get("keys"); err != nil {
+		t.Errorf("expected keys to be kept, got %v", err)
+	}
+}
+
+// TestInMemoryStorageGeneration checks that a value is not stored once one of its tags was
+// invalidated or the cache reset since its generation was read.
+func TestInMemoryStorageGeneration(t *testing.T) {
+	storage := NewInMemoryStorage()
+	defer storage.Close()
+
+	gen, _ := storage.Generation("queue", "success")
+	_ = storage.Invalidate("success")
+	_ = storage.SetTagged("stats", []byte{1}, time.Minute, []string{"queue", "success"}, gen)
+	if _, err := storage.Get("stats"); err == nil {
+		t.Errorf("expected stats not to be stored after an invalidation")
+	}
+
+	gen, _ = storage.Generation("queue", "success")
+	_ = storage.Reset()
+	_ = storage.SetTagged("stats", []byte{1}, time.Minute, []string{"queue", "success"}, gen)
+	if _, err := storage.Get("stats"); err == nil {
+		t.Errorf("expected stats not to be stored after a reset")
+	}
+
+	gen, _ = storage.Generation("queue", "success")
+	_ = storage.SetTagged("stats", []byte{1}, time.Minute, []string{"queue", "success"}, gen)
+	if _, err := storage.Get("stats"); err != nil {
+		t.Errorf("expected stats to be stored, got %v", err)
+	}
+}
+
+// TestInMemoryStorageBounds checks the eviction of the least recently used entries.
+func TestInMemoryStorageBounds(t *testing.T) {
+	t.Run("MaxEntries", func(t *testing.T) {
+		storage := NewBoundedInMemoryStorage(InMemoryOptions{MaxEntries: 2})
+		defer storage.Close()
+
+		_ = storage.Set("a", []byte{1}, time.Minute)
+		_ = storage.SetTagged("b", []byte{2}, time.Minute, []string{"queue"}, 0)
+
+		// Reading a makes b the least recently used entry
+		if _, err := storage.Get("a"); err != nil {
+			t.Fatalf("failed to get a: %v", err)
+		}
+		_ = storage.Set("c", []byte{3}, time.Minute)
+
+		if _, err := storage.Get("b"); err == nil {
+			t.Errorf("expected b to be evicted")
+		}
+		for _, key := range []string{"a", "c"} {
+			if _, err := storage.Get(key); err != nil {
+				t.Errorf("expected %s to be kept, got %v", key, err)
+			}
+		}
+
+		// The evicted key no longer belongs to its tags
+		if len(storage.tags) != 0 {
+			t.Errorf("expected no tags, got %v", storage.tags)
+		}
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		storage := NewBoundedInMemoryStorage(InMemoryOptions{MaxBytes: 10})
+		defer storage.Close()
+
+		_ = storage.Set("a", []byte("1234"), time.Minute) // 5 bytes
+		_ = storage.Set("b", []byte("1234"), time.Minute) // 10 bytes
+		_ = storage.Set("c", []byte("12"), time.Minute)   // 13 bytes, a is evicted
+
+		if _, err := storage.Get("a"); err == nil {
+			t.Errorf("expected a to be evicted")
+		}
+		if stats := storage.Stats(); stats.Bytes != 8 || stats.Entries != 2 || stats.Evictions != 1 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+
+		// A value larger than the cache is not stored and does not evict anything
+		_ = storage.Set("d", make([]byte, 10), time.Minute)
+		if _, err := storage.Get("d"); err == nil {
+			t.Errorf("expected d not to be stored")
+		}
+		if stats := storage.Stats(); stats.Entries != 2 {
+			t.Errorf("expected 2 entries, got %d", stats.Entries)
+		}
+	})
+}
+
+// TestInMemoryStorageStats checks the counters of hits, misses and expirations.
+func TestInMemoryStorageStats(t *testing.T) {
+	storage := NewInMemoryStorage()
+	defer storage.Close()
+
+	_ = storage.Set("key", []byte("value"), time.Minute)
+	_ = storage.Set("short", []byte("value"), time.Millisecond)
+	_ = storage.Set("forever", []byte("value"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	_, _ = storage.Get("key")
+	_, _ = storage.Get("forever")
+	_, _ = storage.Get("short")
+	_, _ = storage.Get("missing")
+
+	want := InMemoryStats{Hits: 2, Misses: 2, Expirations: 1, Entries: 2, Bytes: 20}
+	if stats := storage.Stats(); stats != want {
+		t.Errorf("expected %+v, got %+v", want, stats)
+	}
+}
+
+// TestInMemoryStorageJanitor checks that expired entries are removed without being read
+// until the storage is closed.
+func TestInMemoryStorageJanitor(t *testing.T) {
+	storage := NewBoundedInMemoryStorage(InMemoryOptions{Cleanup: 10 * time.Millisecond})
+
+	_ = storage.Set("key", []byte("value"), time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	if stats := storage.Stats(); stats.Entries != 0 || stats.Expirations != 1 {
+		t.Errorf("expected the janitor to remove the key, got %+v", stats)
+	}
+
+	if err := storage.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+	if err := storage.Close(); err != nil {
+		t.Fatalf("failed to close twice: %v", err)
+	}
+
+	_ = storage.Set("key", []byte("value"), time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	if stats := storage.Stats(); stats.Entries != 1 {
+		t.Errorf("expected the janitor to be stopped, got %+v", stats)
+	}
+}
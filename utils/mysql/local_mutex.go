@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"errors"
+	"log/slog"
 	"sync"
 	"time"
 )
@@ -60,13 +61,13 @@ func (m *LocalMutex) startTimeoutTimer(key string) {
 		// Если блокировка не была снята, удаляем мьютекс
 		if data, exists := m.mx[key]; exists && time.Since(data.lastLock) >= 10*time.Second {
 			delete(m.mx, key)
-			println("Мьютекс для ключа", key, "удален из-за тайм-аута.")
+			slog.Debug("mutex removed after timeout", "key", key)
 		}
 	})
 }
 
-// Блокировка для конкретного ключа
-func (m *LocalMutex) Lock(key string) error {
+// Блокировка для конкретного ключа, токен всегда 0
+func (m *LocalMutex) Lock(key string) (int64, error) {
 	data := m.getMutexForKey(key)
 	data.mu.Lock()
 
@@ -74,11 +75,11 @@ func (m *LocalMutex) Lock(key string) error {
 	data.lastLock = time.Now()
 	m.startTimeoutTimer(key)
 
-	return nil
+	return 0, nil
 }
 
 // Разблокировка для конкретного ключа
-func (m *LocalMutex) Unlock(key string) error {
+func (m *LocalMutex) Unlock(key string, _ int64) error {
 	data := m.getMutexForKey(key)
 	data.mu.Unlock()
 
@@ -117,7 +118,7 @@ func (m *LocalMutex) RUnlock(key string) error {
 	if data.timer != nil {
 		data.timer.Stop()
 		delete(m.mx, key)
-		println("Мьютекс для ключа", key, "разблокирован для чтения и удален.")
+		slog.Debug("mutex read-unlocked and removed", "key", key)
 	}
 	return nil
 }
@@ -14,7 +14,7 @@ func TestLocalMutex(t *testing.T) {
 		key := "testKeyLock"
 
 		// Lock the mutex with a specific key
-		if err := localMutex.Lock(key); err != nil {
+		if _, err := localMutex.Lock(key); err != nil {
 			t.Fatalf("Failed to lock key: %v", err) // Fail the test if Lock returns an error
 		}
 
@@ -24,7 +24,7 @@ func TestLocalMutex(t *testing.T) {
 		}
 
 		// Unlock the mutex with the same key
-		if err := localMutex.Unlock(key); err != nil {
+		if err := localMutex.Unlock(key, 0); err != nil {
 			t.Fatalf("Failed to unlock key: %v", err) // Fail the test if Unlock returns an error
 		}
 
@@ -39,7 +39,7 @@ func TestLocalMutex(t *testing.T) {
 		key := "testKeyTimeout"
 
 		// Lock the mutex with a specific key
-		if err := localMutex.Lock(key); err != nil {
+		if _, err := localMutex.Lock(key); err != nil {
 			t.Fatalf("Failed to lock key: %v", err) // Fail the test if Lock returns an error
 		}
 
@@ -82,7 +82,7 @@ func TestLocalMutex(t *testing.T) {
 		key := "testKeyDelete"
 
 		// Lock the mutex with a specific key
-		if err := localMutex.Lock(key); err != nil {
+		if _, err := localMutex.Lock(key); err != nil {
 			t.Fatalf("Failed to lock key: %v", err) // Fail the test if Lock returns an error
 		}
 
@@ -18,15 +18,17 @@ var (
 
 // Options struct defines configuration parameters for the database connection.
 type Options struct {
-	Host           string  // The database host address (e.g., "localhost" or an IP address).
-	Username       string  // The username to authenticate with the database.
-	Password       string  // The password to authenticate with the database.
-	Database       string  // The name of the specific database to connect to.
-	Port           int     // The port number on which the database server is listening.
-	MaxConnections int     // The maximum number of open database connections.
-	Cache          Storage // A custom cache implementation (implements the Storage interface).
-	CacheEnabled   bool    // A flag indicating whether caching is enabled.
-	Mutex          Mutex   // A custom mutex implementation (implements the Mutex interface).
+	Host           string   // The database host address (e.g., "localhost" or an IP address).
+	Username       string   // The username to authenticate with the database.
+	Password       string   // The password to authenticate with the database.
+	Database       string   // The name of the specific database to connect to.
+	Port           int      // The port number on which the database server is listening.
+	MaxConnections int      // The maximum number of open database connections.
+	Cache          Storage  // A custom cache implementation (implements the Storage interface).
+	CacheEnabled   bool     // A flag indicating whether caching is enabled.
+	Mutex          Mutex    // A custom mutex implementation (implements the Mutex interface).
+	Codec          Codec    // The serialization of cached results, JSON if nil.
+	Observer       Observer // The instrumentation of queries and cache lookups, none if nil.
 }
 
 // SQL struct encapsulates the database connection, cache, and synchronization primitives.
@@ -35,9 +37,11 @@ type CoreEntity struct {
 	prepare      map[string]*sql.Stmt // A map to store prepared SQL statements.
 	cache        Storage              // The storage interface for caching query results.
 	mutex        Mutex                // The mutex interface for synchronizing access.
-	stop         chan bool            // A channel to signal the shutdown of the database connection.
 	mx           sync.RWMutex         // A read-write mutex to synchronize internal access.
 	CacheEnabled bool                 // Indicates whether caching is enabled.
+	refreshing   sync.Map             // The keys of the stale results being refreshed in the background.
+	codec        Codec                // The serialization of cached results, JSON if nil.
+	observer     Observer             // The instrumentation of queries and cache lookups, none if nil.
 }
 
 // Storage interface defines methods for a generic key-value storage system.
@@ -62,19 +66,22 @@ type Storage interface {
 
 // Mutex interface defines methods for locking and unlocking a resource by key.
 type Mutex interface {
-	// Lock attempts to acquire a lock for the given key.
-	Lock(key string) error
+	// Lock attempts to acquire a lock for the given key. It returns the token of the
+	// holder, which must be given back to Unlock, 0 if the mutex hands out no tokens.
+	Lock(key string) (int64, error)
 
-	// Unlock releases the lock for the given key.
-	Unlock(key string) error
+	// Unlock releases the lock for the given key acquired with token.
+	Unlock(key string, token int64) error
 }
 
-// type MySQLError mysql.MySQLError
-
+// MySQLError is the error returned by the functions of this package. It wraps the class
+// of the error, such as ErrDeadlock, and the error of the driver it was built from.
 type MySQLError struct {
 	Number   uint16
 	SQLState [5]byte
 	Message  string
+	kind     error // The class of the error, nil if unclassified
+	cause    error // The error of the driver, nil if built by this package
 }
 
 func (me *MySQLError) Error() string {
@@ -85,13 +92,30 @@ func (me *MySQLError) Error() string {
 	return fmt.Sprintf("Error %d: %s", me.Number, me.Message)
 }
 
+// Is reports whether err is a MySQLError with the same number, state and message, so that
+// errors.Is matches a sentinel such as ErrMySQLNotInitialized and not every error sharing
+// its custom 45000 number. Classes are matched through Unwrap.
 func (me *MySQLError) Is(err error) bool {
 	if merr, ok := err.(*MySQLError); ok {
-		return merr.Number == me.Number
+		return merr.Number == me.Number &&
+			merr.SQLState == me.SQLState &&
+			merr.Message == me.Message
 	}
 	return false
 }
 
+// Unwrap returns the class and the cause of the error for errors.Is and errors.As.
+func (me *MySQLError) Unwrap() []error {
+	var errs []error
+	if me.kind != nil {
+		errs = append(errs, me.kind)
+	}
+	if me.cause != nil {
+		errs = append(errs, me.cause)
+	}
+	return errs
+}
+
 // // Global instance of the SQL struct. It acts as a singleton for the database connection.
 var Core *CoreEntity
 
@@ -121,6 +145,8 @@ func New(opt Options) (*CoreEntity, error) {
 		DB:           db,
 		prepare:      make(map[string]*sql.Stmt), // Initialize the map for prepared statements.
 		CacheEnabled: opt.CacheEnabled,           // Enable caching based on the provided option.
+		codec:        opt.Codec,                  // Serialize cached results with the provided codec.
+		observer:     opt.Observer,               // Report queries and cache lookups to the provided observer.
 	}
 
 	// Set the custom mutex implementation, if provided.
@@ -141,15 +167,19 @@ func New(opt Options) (*CoreEntity, error) {
 
 // Close cleans up resources used by the global MySQL instance.
 func (c *CoreEntity) Close() {
-	// Signal any background processes to stop.
-	c.stop <- true
-
 	// Close all prepared SQL statements.
+	c.mx.Lock()
 	for _, stmt := range c.prepare {
 		if stmt != nil {
 			stmt.Close()
 		}
 	}
+	c.mx.Unlock()
+
+	// Stop the background workers of the cache.
+	if c.cache != nil {
+		c.cache.Close()
+	}
 
 	// Close the database connection.
 	c.DB.Close()
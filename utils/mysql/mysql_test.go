@@ -1,8 +1,11 @@
 package mysql
 
 import (
+	"database/sql"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,3 +26,35 @@ func TestConnectionString(t *testing.T) {
 		assert.Equal(t, expected, actual, "Connection string is not generated correctly")
 	})
 }
+
+// TestClose checks that Close returns once the statements, the cache and the database are closed.
+func TestClose(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+
+	cache := NewInMemoryStorage()
+	c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt), cache: cache}
+
+	mock.ExpectPrepare(`CALL USER_GET\(\?\)`).WillBeClosed()
+	mock.ExpectClose()
+	_, err := c.getPreparedStatement("CALL USER_GET(?)")
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		c.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not return")
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	select {
+	case <-cache.stop:
+	default:
+		t.Error("expected the janitor of the cache to be stopped")
+	}
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import "time"
+
+// Observer receives the instrumentation of a CoreEntity, for example to export it as
+// metrics, so this package does not depend on how it is reported.
+type Observer interface {
+	// ObserveQuery records a statement by label, how long it took and whether it failed.
+	ObserveQuery(label string, duration time.Duration, failed bool)
+
+	// ObserveCache records a lookup in the query cache by result: hit, stale or miss.
+	ObserveCache(result string)
+}
+
+// observe records the duration and outcome of a statement by label.
+func (c *CoreEntity) observe(label string, start time.Time, err *MySQLError) {
+	if c.observer != nil {
+		c.observer.ObserveQuery(label, time.Since(start), err != nil)
+	}
+}
+
+// observeCache records the result of a lookup in the query cache.
+func (c *CoreEntity) observeCache(result string) {
+	if c.observer != nil {
+		c.observer.ObserveCache(result)
+	}
+}
@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Out declares an OUT or INOUT argument of a stored procedure in Params.Args.
+// The value of the parameter is scanned into Dest once the call completes.
+//
+//	var balance int64
+//	mysql.Exec(mysql.Core, mysql.Params{
+//		Exec: "BALANCE_GET",
+//		Args: []any{wallet, mysql.Out{Dest: &balance}},
+//	})
+type Out struct {
+	Dest  any  // Pointer the value of the parameter is scanned into
+	In    any  // Value passed in for an INOUT parameter
+	InOut bool // Whether In is passed to the procedure, otherwise the parameter starts as NULL
+}
+
+// session is the part of *sql.Conn and *sql.Tx needed to call a procedure with OUT
+// parameters, which are bound to session variables of a single connection.
+type session interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// hasOut reports whether args declare an OUT or INOUT parameter.
+func hasOut(args []any) bool {
+	for _, arg := range args {
+		if _, ok := arg.(Out); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// outVariable returns the session variable bound to the i-th argument.
+func outVariable(i int) string {
+	return fmt.Sprintf("@mint_out_%d", i)
+}
+
+// outCall splits params into the statement that initializes the session variables with
+// its arguments, the CALL statement with its arguments, and the query reading the
+// variables back with the destinations of their values.
+func outCall(params Params) (set string, setArgs []any, call string, callArgs []any, get string, dests []any) {
+	sets := []string{}
+	placeholders := []string{}
+	variables := []string{}
+
+	for i, arg := range params.Args {
+		out, ok := arg.(Out)
+		if !ok {
+			placeholders = append(placeholders, "?")
+			callArgs = append(callArgs, arg)
+			continue
+		}
+
+		variable := outVariable(i)
+		placeholders = append(placeholders, variable)
+		variables = append(variables, variable)
+		dests = append(dests, out.Dest)
+
+		// Reset OUT parameters, the connection may keep the values of a previous call
+		if out.InOut {
+			sets = append(sets, variable+" = ?")
+			setArgs = append(setArgs, out.In)
+		} else {
+			sets = append(sets, variable+" = NULL")
+		}
+	}
+
+	set = "SET " + strings.Join(sets, ", ")
+	call = fmt.Sprintf("CALL %v(%v)", params.Exec, strings.Join(placeholders, ", "))
+	get = "SELECT " + strings.Join(variables, ", ")
+	return set, setArgs, call, callArgs, get, dests
+}
+
+// callRows calls the procedure of params on s, hands the rows it returns to callback and
+// reads the OUT parameters back. Results of such calls are never cached.
+func callRows[T any](
+	ctx context.Context,
+	s session,
+	params Params,
+	callback func(rows *sql.Rows) (*T, *MySQLError),
+) (*T, *MySQLError) {
+	set, setArgs, call, callArgs, get, dests := outCall(params)
+
+	if _, err := s.ExecContext(ctx, set, setArgs...); err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+
+	rows, err := s.QueryContext(ctx, call, callArgs...)
+	if err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+	res, clbErr := callback(rows)
+
+	// The variables are only assigned once every result set of the call has been read
+	for rows.NextResultSet() {
+		// Skip the result sets the callback did not read
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+	if clbErr != nil {
+		return res, clbErr
+	}
+
+	if err := s.QueryRowContext(ctx, get).Scan(dests...); err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+	return res, nil
+}
+
+// callExec is callRows for a procedure that returns no rows, reporting the outcome of the call.
+func callExec(ctx context.Context, s session, params Params) (*Result, *MySQLError) {
+	set, setArgs, call, callArgs, get, dests := outCall(params)
+
+	if _, err := s.ExecContext(ctx, set, setArgs...); err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+
+	res, err := s.ExecContext(ctx, call, callArgs...)
+	if err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+
+	if err := s.QueryRowContext(ctx, get).Scan(dests...); err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+	return newResult(res), nil
+}
+
+// queryOut runs a call with OUT parameters on a dedicated connection of c, so the session
+// variables are not shared with other queries.
+func queryOut[T any](
+	ctx context.Context,
+	c *CoreEntity,
+	params Params,
+	callback func(rows *sql.Rows) (*T, *MySQLError),
+) (*T, *MySQLError) {
+	conn, err := c.DB.Conn(ctx)
+	if err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+	defer conn.Close()
+
+	return callRows(ctx, conn, params, callback)
+}
+
+// execOut runs a call with OUT parameters that returns no rows on a dedicated connection of c.
+func execOut(ctx context.Context, c *CoreEntity, params Params) (*Result, *MySQLError) {
+	conn, err := c.DB.Conn(ctx)
+	if err != nil {
+		return nil, convertContextError(ctx, err)
+	}
+	defer conn.Close()
+
+	return callExec(ctx, conn, params)
+}
+
+// ResultSets hands the result sets returned by a procedure to handlers, one per set in
+// order, for use in a Query callback:
+//
+//	mysql.Query(mysql.Core, params, func(rows *sql.Rows) (*Page, *mysql.MySQLError) {
+//		page := &Page{}
+//		return page, mysql.ResultSets(rows, page.scanItems, page.scanTotal)
+//	})
+//
+// A missing result set is an error, extra ones are ignored.
+func ResultSets(rows *sql.Rows, handlers ...func(rows *sql.Rows) *MySQLError) *MySQLError {
+	for i, handler := range handlers {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return convertError(err)
+			}
+			return NewError(fmt.Errorf("result set %d is missing", i+1))
+		}
+
+		if err := handler(rows); err != nil {
+			return err
+		}
+		if err := rows.Err(); err != nil {
+			return convertError(err)
+		}
+	}
+	return nil
+}
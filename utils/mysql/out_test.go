@@ -0,0 +1,156 @@
+package mysql
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutCall(t *testing.T) {
+	var total, counter int64
+	set, setArgs, call, callArgs, get, dests := outCall(Params{
+		Exec: "USER_COUNT",
+		Args: []any{"active", Out{Dest: &total}, Out{Dest: &counter, In: 5, InOut: true}},
+	})
+
+	assert.Equal(t, "SET @mint_out_1 = NULL, @mint_out_2 = ?", set)
+	assert.Equal(t, []any{5}, setArgs)
+	assert.Equal(t, "CALL USER_COUNT(?, @mint_out_1, @mint_out_2)", call)
+	assert.Equal(t, []any{"active"}, callArgs)
+	assert.Equal(t, "SELECT @mint_out_1, @mint_out_2", get)
+	assert.Equal(t, []any{&total, &counter}, dests)
+}
+
+// TestOut contains test cases for procedures with OUT parameters.
+func TestOut(t *testing.T) {
+	// Test case: Rows and OUT parameters of the same call
+	t.Run("Query", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt), CacheEnabled: true, cache: NewMockCache(), mutex: &MockMutex{}}
+
+		mock.ExpectExec(regexp.QuoteMeta("SET @mint_out_1 = NULL")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("CALL USER_PAGE(?, @mint_out_1)")).WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT @mint_out_1")).
+			WillReturnRows(sqlmock.NewRows([]string{"@mint_out_1"}).AddRow(42))
+
+		var total int64
+		result, err := Query(c, Params{
+			Exec:       "USER_PAGE",
+			Args:       []any{10, Out{Dest: &total}},
+			CacheDelay: 1,
+		}, func(rows *sql.Rows) (*[]User, *MySQLError) {
+			users := []User{}
+			for rows.Next() {
+				var user User
+				_ = rows.Scan(&user.ID, &user.Name)
+				users = append(users, user)
+			}
+			return &users, nil
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []User{{ID: 1, Name: "John Doe"}}, *result)
+		assert.Equal(t, int64(42), total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Test case: An INOUT parameter without rows
+	t.Run("Exec", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectExec(regexp.QuoteMeta("SET @mint_out_0 = ?")).WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CALL COUNTER_ADD(@mint_out_0)")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT @mint_out_0")).
+			WillReturnRows(sqlmock.NewRows([]string{"@mint_out_0"}).AddRow(6))
+
+		var counter int64
+		result, err := Exec(c, Params{Exec: "COUNTER_ADD", Args: []any{Out{Dest: &counter, In: 5, InOut: true}}})
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), result.RowsAffected)
+		assert.Equal(t, int64(6), counter)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestResultSets contains test cases for procedures returning several result sets.
+func TestResultSets(t *testing.T) {
+	type page struct {
+		Users []User
+		Total int
+	}
+
+	query := func(c *CoreEntity) (*page, *MySQLError) {
+		return Query(c, Params{Exec: "USER_PAGE", Args: []any{10}}, func(rows *sql.Rows) (*page, *MySQLError) {
+			result := &page{}
+			return result, ResultSets(rows,
+				func(rows *sql.Rows) *MySQLError {
+					for rows.Next() {
+						var user User
+						if err := rows.Scan(&user.ID, &user.Name); err != nil {
+							return NewError(err)
+						}
+						result.Users = append(result.Users, user)
+					}
+					return nil
+				},
+				func(rows *sql.Rows) *MySQLError {
+					if rows.Next() {
+						if err := rows.Scan(&result.Total); err != nil {
+							return NewError(err)
+						}
+					}
+					return nil
+				},
+			)
+		})
+	}
+
+	// Test case: Every result set is handed to its handler
+	t.Run("Every Result Set", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectPrepare(`CALL USER_PAGE\(\?\)`).ExpectQuery().WithArgs(10).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe").AddRow(2, "Jane Doe"),
+			sqlmock.NewRows([]string{"total"}).AddRow(25),
+		)
+
+		result, err := query(c)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &page{Users: []User{{1, "John Doe"}, {2, "Jane Doe"}}, Total: 25}, result)
+	})
+
+	// Test case: A missing result set is an error
+	t.Run("Missing Result Set", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectPrepare(`CALL USER_PAGE\(\?\)`).ExpectQuery().WithArgs(10).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"),
+		)
+
+		_, err := query(c)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "result set 2 is missing", err.Message)
+	})
+}
@@ -3,14 +3,13 @@ package mysql
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"strings"
 
 	"time"
 
-	"github.com/go-sql-driver/mysql"
-	jsoniter "github.com/json-iterator/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Params is a structure for storing query parameters used in the Query function
@@ -21,6 +20,23 @@ type Params struct {
 	Args       []any         // Arguments for the SQL query
 	Timeout    time.Duration // Timeout for the query execution
 	CacheDelay time.Duration // Cache delay time (time to keep data in cache)
+	Tags       []string      // Tags of the cached result, evicted by writes invalidating any of them
+	Invalidate []string      // Tags whose cached results are evicted once the statement succeeds
+
+	// StaleDelay is the time a result is still served once CacheDelay expired, while a single
+	// background query refreshes it.
+	//
+	// WARNING: the callback then runs in a goroutine of its own, after the caller has already
+	// returned with the stale result, and its context is detached from the caller's cancellation.
+	// It must only read the rows it is given: a callback capturing request-scoped state, such
+	// as a *gin.Context, a buffer or variables of the caller, races with the caller reusing it.
+	StaleDelay time.Duration
+	// NegativeDelay is the time to keep a result not found (a nil result without error) in
+	// cache, usually shorter than CacheDelay. Such results are not cached if it is 0.
+	NegativeDelay time.Duration
+	// EarlyExpiry makes results expire early at random, the sooner the longer the query took,
+	// so the callers of a popular key do not all miss at once. 0 disables it, 1 is a good start.
+	EarlyExpiry float64
 }
 
 // getPreparedStatement retrieves a prepared SQL statement from the cache or prepares a new one
@@ -50,152 +66,224 @@ func Query[T any](
 	params Params,
 	callback func(rows *sql.Rows) (*T, *MySQLError),
 ) (*T, *MySQLError) {
+	return QueryContext(context.Background(), c, params, callback)
+}
+
+// QueryContext is Query bounded by ctx: the query is canceled with ctx, and params.Timeout
+// still applies as an upper bound.
+func QueryContext[T any](
+	ctx context.Context,
+	c *CoreEntity,
+	params Params,
+	callback func(rows *sql.Rows) (*T, *MySQLError),
+) (*T, *MySQLError) {
+	label := labelOf(params)
+
+	ctx, span := startSpan(ctx, "mysql "+label,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			keyExec.String(label),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	res, err := query(ctx, c, params, callback)
+	c.observe(label, start, err)
+	if err != nil {
+		fail(span, err)
+		return res, err
+	}
+
+	// Evict the cached results this statement made stale
+	c.evict(ctx, params.Invalidate)
+	return res, nil
+}
 
-	query := params.Query
-	if params.Query == "" {
-		args := strings.TrimRight(strings.Repeat("?, ", len(params.Args)), ", ")
-		query = fmt.Sprintf("CALL %v(%v)", params.Exec, args)
+// labelOf returns the name a query is instrumented under. Raw queries share one label
+// to keep the cardinality low.
+func labelOf(params Params) string {
+	if params.Query != "" {
+		return "query"
+	}
+	return params.Exec
+}
+
+// statement returns the raw query, or a CALL of the stored procedure with a placeholder per argument.
+func statement(params Params) string {
+	if params.Query != "" {
+		return params.Query
+	}
+	args := strings.TrimRight(strings.Repeat("?, ", len(params.Args)), ", ")
+	return fmt.Sprintf("CALL %v(%v)", params.Exec, args)
+}
+
+// query implements Query without instrumentation.
+func query[T any](
+	parent context.Context,
+	c *CoreEntity,
+	params Params,
+	callback func(rows *sql.Rows) (*T, *MySQLError),
+) (*T, *MySQLError) {
+
+	// Calls with OUT parameters run on a dedicated connection and are never cached
+	if params.Query == "" && hasOut(params.Args) {
+		ctx, cancel := createContextWithTimeout(parent, params.Timeout)
+		defer cancel()
+		return queryOut(ctx, c, params, callback)
 	}
 
 	key := params.Key
 	// If no key is provided, generate one from the query and arguments
 	if key == "" {
-		key = CreateKey(query, params.Args...)
+		key = CreateKey(statement(params), params.Args...)
 	}
 
 	// If caching is enabled and a cache delay is specified, try fetching data from the cache first
 	if c.CacheEnabled && params.CacheDelay > 0 {
 
-		mutexKey := fmt.Sprintf("mutex_%v", key)
+		mutexKey := mutexKeyOf(key)
 
 		// Try to fetch data from the cache
-		data := check[T](c, key)
-		if data != nil {
-			res, ok := data.(*T)
-			if !ok {
-				return nil, nil
+		e, found := lookup(c, key)
+		if found && !e.expired(time.Now(), params.EarlyExpiry) {
+			if res, ok := decodeResult[T](e); ok {
+				c.observeCache("hit")
+				return res, nil // If data is found in cache, return it immediately
 			}
-			return res, nil // If data is found in cache, return it immediately
 		}
 
+		// Serve a stale result while a single background query refreshes it
+		if found && params.StaleDelay > 0 {
+			if res, ok := decodeResult[T](e); ok {
+				c.observeCache("stale")
+				refresh(parent, c, params, key, e, callback)
+				return res, nil
+			}
+		}
+		c.observeCache("miss")
+
 		// If data is not found in cache, lock access for other queries with the same key
-		if err := c.mutex.Lock(mutexKey); err != nil {
-			return nil, nil // If locking fails, return nil (no data)
+		token, err := c.mutex.Lock(mutexKey)
+		if err != nil {
+			return nil, lockError(err) // The caller may retry once the holder is done
 		}
-		defer c.mutex.Unlock(mutexKey) // Unlock the mutex after the execution
-
-		// Recheck the cache after locking
-		data = check[T](c, key)
-		if data != nil {
-			res, ok := data.(*T)
-			if !ok {
-				return nil, nil
+		defer c.mutex.Unlock(mutexKey, token) // Unlock the mutex after the execution
+
+		// Recheck the cache after locking. A result that expired early is only
+		// reused if another caller refreshed it in the meantime.
+		if again, ok := lookup(c, key); ok && !again.expired(time.Now(), 0) &&
+			(!found || !again.fresh.Equal(e.fresh)) {
+			if res, ok := decodeResult[T](again); ok {
+				return res, nil // If data is found in cache, return it immediately
 			}
-			return res, nil // If data is found in cache, return it immediately
 		}
 
 	}
 
+	return fetch(parent, c, params, key, callback)
+}
+
+// fetch runs the query and caches its result under key when params ask for it.
+func fetch[T any](
+	parent context.Context,
+	c *CoreEntity,
+	params Params,
+	key string,
+	callback func(rows *sql.Rows) (*T, *MySQLError),
+) (*T, *MySQLError) {
 	// Create a context with a timeout for the query execution
-	ctx, cancel := createContextWithTimeout(params.Timeout)
+	ctx, cancel := createContextWithTimeout(parent, params.Timeout)
 	defer cancel() // Cancel the context after the query execution
 
 	// Retrieve the prepared statement
-	prepare, err := c.getPreparedStatement(query)
+	prepare, err := c.getPreparedStatement(statement(params))
 	if err != nil {
-		sqlErr, ok := err.(*mysql.MySQLError)
-		if ok {
-			return nil, &MySQLError{
-				Number:   sqlErr.Number,
-				SQLState: sqlErr.SQLState,
-				Message:  sqlErr.Message,
-			} // Return MySQL-specific error if encountered
-		}
-		return nil, &MySQLError{} // If it's not a MySQL error, return a generic empty MySQL error
+		return nil, convertError(err)
 	}
 
+	// A write invalidating the tags while the query runs makes its result stale
+	gen, cacheable := c.generation(params)
+
+	start := time.Now()
+
 	// Execute the query with the provided arguments
 	rows, err := prepare.QueryContext(ctx, params.Args...)
 	if err != nil {
-		sqlErr, ok := err.(*mysql.MySQLError)
-		// Check for specific error code 1213 (deadlock) and return a custom error
-		if ok && sqlErr.Number == 1213 {
-			return nil, &MySQLError{
-				Number:  45000,
-				Message: "DEADLOCK", // Custom error for deadlock
-			}
-		}
-
-		// Check if the error is a timeout
-		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, &MySQLError{
-				Number:  45000,
-				Message: "TIMEOUT", // Custom error for query timeout
-			}
-		}
-
-		// Return the SQL error if it is any other error
-		return nil, &MySQLError{
-			Number:   sqlErr.Number,
-			SQLState: sqlErr.SQLState,
-			Message:  sqlErr.Message,
-		}
+		return nil, convertContextError(ctx, err)
 	}
 	defer rows.Close() // Close the rows after finishing the query
 
 	// Call the callback function to process the rows and extract the result
 	clbRes, clbErr := callback(rows)
 
-	// Serialize the result of the callback function
-	res, err := jsoniter.Marshal(clbRes)
-	if err != nil {
-		// If serialization fails, return a custom error indicating serialization failure
-		return clbRes, &MySQLError{
-			Number:  45000,
-			Message: "SERIALIZE", // Custom error for serialization issues
-		}
-	}
-
 	// If caching is enabled and no errors occurred, store the result in the cache
 	if c.CacheEnabled &&
 		params.CacheDelay > 0 &&
-		clbErr == nil &&
-		clbRes != nil {
-		_ = c.cache.Set(key, res, params.CacheDelay) // Cache the result with the given delay
+		cacheable &&
+		clbErr == nil {
+		delta := time.Since(start)
+
+		// Serialize the result of the callback function, a result not found has no payload
+		var res []byte
+		if clbRes != nil {
+			res, err = c.codecOf().Marshal(clbRes)
+			if err != nil {
+				// If serialization fails, return a custom error indicating serialization failure
+				return clbRes, &MySQLError{
+					Number:  45000,
+					Message: "SERIALIZE", // Custom error for serialization issues
+					kind:    ErrSerialize,
+					cause:   err,
+				}
+			}
+		}
+		_ = c.keep(key, res, clbRes == nil, delta, params, gen) // Cache the result with the given delay
 	}
 
 	// Return the result and any potential MySQL error from the callback
 	return clbRes, clbErr
 }
 
-// createContextWithTimeout creates a context with a timeout duration for the query
-func createContextWithTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+// mutexKeyOf returns the key of the mutex guarding the cached result under key.
+func mutexKeyOf(key string) string {
+	return fmt.Sprintf("mutex_%v", key)
+}
+
+// createContextWithTimeout derives a context of parent with a timeout duration for the query
+func createContextWithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 	// Set a default timeout of 100 seconds if the timeout is zero
 	if timeout == 0 {
 		timeout = 100 * time.Second
 	}
-	return context.WithTimeout(context.Background(), timeout)
+	return context.WithTimeout(parent, timeout)
 }
 
-// check attempts to retrieve data from the cache for the given key and returns it
-func check[T any](c *CoreEntity, key string) any {
-	// Attempt to get the data from the cache
-	data, err := c.cache.Get(key)
+// decodeResult deserializes a cached result with the codec of its format. A result not
+// found decodes to nil.
+func decodeResult[T any](e entry) (*T, bool) {
+	if e.missing {
+		return nil, true
+	}
 
-	// Return nil if an error occurred while fetching data from the cache
-	if err != nil {
-		return nil
+	codec, ok := codecs[e.format]
+	if !ok {
+		return nil, false
 	}
 
 	// Deserialize the cached data into the result variable
 	var res T
-	err = jsoniter.Unmarshal(data, &res)
-
-	// Return nil if deserialization failed
-	if err != nil {
-		return nil
+	if err := codec.Unmarshal(e.payload, &res); err != nil {
+		return nil, false
 	}
+	return &res, true
+}
 
-	// Return the deserialized result
-	return &res
+// codecOf returns the codec serializing the results cached by c, JSON by default.
+func (c *CoreEntity) codecOf() Codec {
+	if c.codec == nil {
+		return JSON
+	}
+	return c.codec
 }
@@ -59,12 +59,12 @@ func (m *MockCache) Close() error {
 type MockMutex struct{}
 
 // Lock simulates acquiring a lock for a given key.
-func (m *MockMutex) Lock(key string) error {
-	return nil
+func (m *MockMutex) Lock(key string) (int64, error) {
+	return 0, nil
 }
 
 // Unlock simulates releasing a lock for a given key.
-func (m *MockMutex) Unlock(key string) error {
+func (m *MockMutex) Unlock(key string, token int64) error {
 	return nil
 }
 
@@ -237,3 +237,290 @@ func TestQuery(t *testing.T) {
 		assert.Equal(t, "DEADLOCK", err.Message)   // Custom deadlock error message
 	})
 }
+
+// TestQueryContext checks that the caller's context bounds the query.
+func TestQueryContext(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+	query := "SELECT id, name FROM users WHERE id = ?"
+	mock.ExpectPrepare(query).ExpectQuery().WithArgs(1).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := QueryContext(ctx, c, Params{Query: query, Args: []any{1}, Timeout: 5 * time.Second}, func(rows *sql.Rows) (*User, *MySQLError) {
+		return &User{}, nil
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrCanceled)
+}
+
+// recordingObserver is an Observer keeping what it receives.
+type recordingObserver struct {
+	queries []string // Labels of the statements, suffixed with " failed" when they failed
+	lookups []string // Results of the cache lookups
+}
+
+func (o *recordingObserver) ObserveQuery(label string, duration time.Duration, failed bool) {
+	if failed {
+		label += " failed"
+	}
+	o.queries = append(o.queries, label)
+}
+
+func (o *recordingObserver) ObserveCache(result string) {
+	o.lookups = append(o.lookups, result)
+}
+
+// TestQueryObserver checks that queries and cache lookups are reported to the observer.
+func TestQueryObserver(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	observer := &recordingObserver{}
+	c := &CoreEntity{
+		DB:           db,
+		prepare:      make(map[string]*sql.Stmt),
+		CacheEnabled: true,
+		cache:        NewInMemoryStorage(),
+		mutex:        &MockMutex{},
+		observer:     observer,
+	}
+
+	read := func(exec string) *MySQLError {
+		_, err := Query(c, Params{Exec: exec, Args: []any{1}, CacheDelay: time.Minute}, func(rows *sql.Rows) (*User, *MySQLError) {
+			return &User{}, nil
+		})
+		return err
+	}
+
+	mock.ExpectPrepare(`CALL USER_GET\(\?\)`).ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectPrepare(`CALL USER_LIST\(\?\)`).ExpectQuery().WithArgs(1).WillReturnError(&mysql.MySQLError{Number: 1213})
+
+	assert.Nil(t, read("USER_GET"))
+	assert.Nil(t, read("USER_GET"))
+	assert.NotNil(t, read("USER_LIST"))
+
+	assert.Equal(t, []string{"USER_GET", "USER_GET", "USER_LIST failed"}, observer.queries)
+	assert.Equal(t, []string{"miss", "hit", "miss"}, observer.lookups)
+}
+
+// lockedMutex is a Mutex whose locks are never acquired.
+type lockedMutex struct{}
+
+func (lockedMutex) Lock(key string) (int64, error)       { return 0, ErrLockTimeout }
+func (lockedMutex) Unlock(key string, token int64) error { return nil }
+
+// TestQueryLockTimeout checks that a cached read whose lock is not acquired fails with a
+// classified error instead of an empty result.
+func TestQueryLockTimeout(t *testing.T) {
+	db, _, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt), CacheEnabled: true, cache: NewMockCache(), mutex: lockedMutex{}}
+
+	result, err := Query(c, Params{Exec: "USER_GET", CacheDelay: time.Minute}, func(rows *sql.Rows) (*User, *MySQLError) {
+		return &User{}, nil
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrLock)
+	assert.ErrorIs(t, err, ErrLockTimeout)
+}
+
+// TestQueryInvalidate checks that a write evicts the cached reads sharing one of its tags.
+func TestQueryInvalidate(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	c := &CoreEntity{
+		DB:           db,
+		prepare:      make(map[string]*sql.Stmt),
+		CacheEnabled: true,
+		cache:        NewInMemoryStorage(),
+		mutex:        &MockMutex{},
+	}
+
+	read := func() *User {
+		result, err := Query(c, Params{
+			Exec:       "USER_GET",
+			Args:       []any{1},
+			CacheDelay: time.Minute,
+			Tags:       []string{"users"},
+		}, func(rows *sql.Rows) (*User, *MySQLError) {
+			var data User
+			if rows.Next() {
+				_ = rows.Scan(&data.ID, &data.Name)
+			}
+			return &data, nil
+		})
+		assert.Nil(t, err)
+		return result
+	}
+
+	prepare := mock.ExpectPrepare(`CALL USER_GET\(\?\)`)
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+	mock.ExpectPrepare(`CALL USER_RENAME\(\?, \?\)`).ExpectExec().WithArgs(1, "Jane Doe").WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Jane Doe"))
+
+	// The second read is served from the cache
+	assert.Equal(t, "John Doe", read().Name)
+	assert.Equal(t, "John Doe", read().Name)
+
+	_, err := Exec(c, Params{Exec: "USER_RENAME", Args: []any{1, "Jane Doe"}, Invalidate: []string{"users"}})
+	assert.Nil(t, err)
+
+	// The write evicted the cached read
+	assert.Equal(t, "Jane Doe", read().Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestQueryInvalidatedWhileRunning checks that a read whose tags are invalidated while it
+// runs does not cache its result, which predates the write.
+func TestQueryInvalidatedWhileRunning(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	c := &CoreEntity{
+		DB:           db,
+		prepare:      make(map[string]*sql.Stmt),
+		CacheEnabled: true,
+		cache:        NewInMemoryStorage(),
+		mutex:        &MockMutex{},
+	}
+
+	read := func(during func()) *User {
+		result, err := Query(c, Params{
+			Exec:       "USER_GET",
+			Args:       []any{1},
+			CacheDelay: time.Minute,
+			Tags:       []string{"users"},
+		}, func(rows *sql.Rows) (*User, *MySQLError) {
+			var data User
+			if rows.Next() {
+				_ = rows.Scan(&data.ID, &data.Name)
+			}
+			during()
+			return &data, nil
+		})
+		assert.Nil(t, err)
+		return result
+	}
+
+	prepare := mock.ExpectPrepare(`CALL USER_GET\(\?\)`)
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Jane Doe"))
+
+	// A write lands once the first read has its rows
+	assert.Equal(t, "John Doe", read(func() { assert.NoError(t, c.invalidate([]string{"users"})) }).Name)
+
+	// The first result was not cached, the second read queries again
+	assert.Equal(t, "Jane Doe", read(func() {}).Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestQueryStaleWhileRevalidate checks that an expired result is served while a single
+// background query refreshes it.
+func TestQueryStaleWhileRevalidate(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	c := &CoreEntity{
+		DB:           db,
+		prepare:      make(map[string]*sql.Stmt),
+		CacheEnabled: true,
+		cache:        NewInMemoryStorage(),
+		mutex:        NewLocalMutex(),
+	}
+
+	params := Params{
+		Key:        "user",
+		Exec:       "USER_GET",
+		Args:       []any{1},
+		CacheDelay: 50 * time.Millisecond,
+		StaleDelay: time.Minute,
+	}
+	read := func() string {
+		result, err := Query(c, params, func(rows *sql.Rows) (*User, *MySQLError) {
+			var data User
+			if rows.Next() {
+				_ = rows.Scan(&data.ID, &data.Name)
+			}
+			return &data, nil
+		})
+		assert.Nil(t, err)
+		return result.Name
+	}
+
+	prepare := mock.ExpectPrepare(`CALL USER_GET\(\?\)`)
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Jane Doe")).
+		WillDelayFor(20 * time.Millisecond)
+
+	assert.Equal(t, "John Doe", read())
+	time.Sleep(60 * time.Millisecond)
+
+	// Both callers get the stale result, only one refresh runs
+	assert.Equal(t, "John Doe", read())
+	assert.Equal(t, "John Doe", read())
+	assert.Eventually(t, func() bool {
+		_, busy := c.refreshing.Load("user")
+		return !busy
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, "Jane Doe", read())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestQueryNegativeCache checks that a result not found is only cached with a NegativeDelay.
+func TestQueryNegativeCache(t *testing.T) {
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	c := &CoreEntity{
+		DB:           db,
+		prepare:      make(map[string]*sql.Stmt),
+		CacheEnabled: true,
+		cache:        NewMockCache(),
+		mutex:        &MockMutex{},
+	}
+
+	read := func(negative time.Duration) {
+		result, err := Query(c, Params{
+			Exec:          "USER_GET",
+			Args:          []any{1},
+			CacheDelay:    time.Minute,
+			NegativeDelay: negative,
+		}, func(rows *sql.Rows) (*User, *MySQLError) {
+			return nil, nil
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, result)
+	}
+
+	prepare := mock.ExpectPrepare(`CALL USER_GET\(\?\)`)
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	// Without NegativeDelay, nothing is cached
+	read(0)
+	assert.Empty(t, c.cache.(*MockCache).storage)
+
+	// With it, the second read does not reach the database
+	read(time.Second)
+	read(time.Second)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
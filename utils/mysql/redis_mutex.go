@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockTimeout is returned by RedisMutex.Lock when the lock is not acquired in time.
+var ErrLockTimeout = errors.New("lock timeout")
+
+// ErrLockLost is returned by RedisMutex.Unlock when the lock expired and may have been
+// acquired by someone else in the meantime.
+var ErrLockLost = errors.New("lock lost")
+
+// lockScript sets the lock with SET NX PX and, once acquired, stores a new fencing token
+// in it, so tokens grow in the order locks are acquired. It returns 0 if the lock is held.
+var lockScript = redis.NewScript(`
+if not redis.call("SET", KEYS[1], "", "NX", "PX", ARGV[1]) then
+	return 0
+end
+local token = redis.call("INCR", KEYS[2])
+redis.call("SET", KEYS[1], token, "XX", "PX", ARGV[1])
+return token
+`)
+
+// renewScript extends the lock only while it still holds the token of the caller.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// unlockScript deletes the lock only while it still holds the token of the caller.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisMutex implements Mutex on a Redis server, so a lock excludes every instance.
+// A lock is a key set with SET NX PX holding a fencing token: a number taken from a
+// single counter that grows with every lock, so a resource can reject writes of a holder
+// whose lock has expired. The lock is renewed every third of TTL while it is held, so a
+// slow query keeps it, and expires after TTL if its holder dies.
+//
+// Only a single Redis server is supported, not a Cluster: acquiring a lock also increments
+// the counter, a key in another slot.
+type RedisMutex struct {
+	client *redis.Client // The client of the Redis server, owned by the caller
+	prefix string        // The prefix of every key written by this mutex
+
+	TTL     time.Duration // Time after which a lock is released if its holder stops renewing it
+	Retry   time.Duration // Delay between two attempts to acquire a lock
+	Timeout time.Duration // Time after which Lock gives up with ErrLockTimeout, longer than TTL to outwait a dead holder
+
+	leases map[int64]chan struct{} // Stops the renewal of every lock held by this process, by token
+	mx     sync.Mutex              // A mutex to synchronize access to leases
+}
+
+// NewRedisMutex creates a RedisMutex on top of client, storing locks under prefix.
+func NewRedisMutex(client *redis.Client, prefix string) *RedisMutex {
+	return &RedisMutex{
+		client:  client,
+		prefix:  prefix,
+		TTL:     10 * time.Second,
+		Retry:   10 * time.Millisecond,
+		Timeout: 30 * time.Second,
+		leases:  make(map[int64]chan struct{}),
+	}
+}
+
+// Lock acquires the lock for the given key, waiting while another holder has it. It returns
+// the fencing token of the lock: tokens grow with every lock, so a resource that remembers
+// the highest token it has seen can reject a holder whose lock expired.
+func (m *RedisMutex) Lock(key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.Timeout)
+	defer cancel()
+
+	keys := []string{m.prefix + "lock:" + key, m.prefix + "fence"}
+	for {
+		fence, err := lockScript.Run(ctx, m.client, keys, m.TTL.Milliseconds()).Int64()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return 0, ErrLockTimeout
+			}
+			return 0, err
+		}
+		if fence != 0 {
+			m.renew(key, fence)
+			return fence, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ErrLockTimeout
+		case <-time.After(m.Retry):
+		}
+	}
+}
+
+// Unlock releases the lock for the given key if it is still held with token, so a holder
+// whose lock expired never releases the lock another holder acquired since.
+func (m *RedisMutex) Unlock(key string, token int64) error {
+	m.mx.Lock()
+	if stop, ok := m.leases[token]; ok {
+		close(stop)
+		delete(m.leases, token)
+	}
+	m.mx.Unlock()
+
+	deleted, err := unlockScript.Run(context.Background(), m.client, []string{m.prefix + "lock:" + key}, strconv.FormatInt(token, 10)).Int()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// renew extends the lock of token every third of TTL until it is unlocked or lost.
+func (m *RedisMutex) renew(key string, token int64) {
+	stop := make(chan struct{})
+	m.mx.Lock()
+	m.leases[token] = stop
+	m.mx.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(m.TTL / 3)
+		defer ticker.Stop()
+
+		keys := []string{m.prefix + "lock:" + key}
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			renewed, err := renewScript.Run(context.Background(), m.client, keys, token, m.TTL.Milliseconds()).Int()
+			if err == nil && renewed == 0 {
+				return // The lock expired, Unlock reports it
+			}
+		}
+	}()
+}
@@ -0,0 +1,242 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStorage implements Storage on a Redis server, so every instance shares the cache.
+// Keys are namespaced by a prefix, which lets several services share one server.
+//
+// Only a single Redis server is supported, not a Cluster: the scripts keeping tags
+// consistent touch keys they cannot declare in advance, which may live in other slots.
+type RedisStorage struct {
+	client *redis.Client // The client of the Redis server, owned by the caller
+	prefix string        // The prefix of every key written by this storage
+}
+
+// NewRedisStorage creates a RedisStorage on top of client, storing keys under prefix.
+func NewRedisStorage(client *redis.Client, prefix string) *RedisStorage {
+	return &RedisStorage{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Get retrieves the value associated with the given key.
+// It returns `nil, nil` if the key does not exist or has expired.
+func (r *RedisStorage) Get(key string) ([]byte, error) {
+	val, err := r.client.Get(context.Background(), r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// SetNX stores a key-value pair that expires after exp only if the key does not exist, with
+// a single SET NX, and reports whether it was stored.
+func (r *RedisStorage) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	return r.client.SetNX(context.Background(), r.prefix+key, val, exp).Result()
+}
+
+// Set stores a key-value pair that expires after exp. A duration of 0 means no expiration.
+// A key overwritten this way leaves the tags of its previous value.
+func (r *RedisStorage) Set(key string, val []byte, exp time.Duration) error {
+	return setScript.Run(context.Background(), r.client, r.keys(key), val, exp.Milliseconds()).Err()
+}
+
+// Every tagged key has a set listing its tags next to it, so the key leaves them once it is
+// overwritten, deleted or invalidated through another tag. The scripts below receive the
+// key and that set as KEYS[1] and KEYS[2].
+
+// untag removes KEYS[1] from the tags of its previous value.
+const untag = `
+local old = redis.call("SMEMBERS", KEYS[2])
+for i = 1, #old do
+	redis.call("SREM", old[i], KEYS[1])
+end
+redis.call("DEL", KEYS[2])
+`
+
+// set stores the value ARGV[1] under KEYS[1] for ARGV[2] milliseconds, 0 meaning forever.
+const set = `
+local exp = tonumber(ARGV[2])
+if exp > 0 then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", exp)
+else
+	redis.call("SET", KEYS[1], ARGV[1])
+end
+`
+
+// setScript stores the value without tags.
+var setScript = redis.NewScript(untag + set + `
+return 1
+`)
+
+// setTaggedScript stores the value and adds its key to every tag in KEYS[3:], followed by
+// the generations of the tags. Nothing is stored if their sum is no longer ARGV[3], one of
+// the tags was then invalidated since. A tag lives at least as long as its longest-lived
+// key. Keys that expired are not removed from their tags by Redis, so a few members of
+// every tag are checked and pruned on each write.
+var setTaggedScript = redis.NewScript(`
+local n = (#KEYS - 2) / 2
+local gen = 0
+for i = 3 + n, #KEYS do
+	gen = gen + tonumber(redis.call("GET", KEYS[i]) or 0)
+end
+if gen ~= tonumber(ARGV[3]) then
+	return 0
+end
+` + untag + set + `
+for i = 3, 2 + n do
+	local sample = redis.call("SRANDMEMBER", KEYS[i], 8)
+	for j = 1, #sample do
+		if redis.call("EXISTS", sample[j]) == 0 then
+			redis.call("SREM", KEYS[i], sample[j])
+		end
+	end
+
+	local ttl = redis.call("PTTL", KEYS[i])
+	redis.call("SADD", KEYS[i], KEYS[1])
+	redis.call("SADD", KEYS[2], KEYS[i])
+	if exp == 0 then
+		redis.call("PERSIST", KEYS[i])
+	elseif ttl == -2 or (ttl >= 0 and ttl < exp) then
+		redis.call("PEXPIRE", KEYS[i], exp)
+	end
+end
+if exp > 0 then
+	redis.call("PEXPIRE", KEYS[2], exp)
+end
+return 1
+`)
+
+// deleteScript removes the value and its key from its tags.
+var deleteScript = redis.NewScript(untag + `
+return redis.call("DEL", KEYS[1])
+`)
+
+// invalidateScript removes every key of the tags in KEYS, and the tags themselves, then
+// increments their generations, following the tags in KEYS. A key is also removed from its
+// other tags, found in the set next to it named by ARGV[1].
+var invalidateScript = redis.NewScript(`
+local prefix = ARGV[1]
+local n = #KEYS / 2
+for i = 1, n do
+	local members = redis.call("SMEMBERS", KEYS[i])
+	for j = 1, #members do
+		local tags = prefix .. "tags:" .. string.sub(members[j], #prefix + 1)
+		local others = redis.call("SMEMBERS", tags)
+		for k = 1, #others do
+			if others[k] ~= KEYS[i] then
+				redis.call("SREM", others[k], members[j])
+			end
+		end
+		redis.call("DEL", members[j], tags)
+	end
+	redis.call("DEL", KEYS[i])
+	redis.call("INCR", KEYS[n + i])
+end
+return 1
+`)
+
+// SetTagged stores a key-value pair like Set and adds the key to every given tag, unless
+// one of the tags was invalidated since Generation returned gen for them. The check and
+// the writes happen in a single script, so a concurrent invalidation never misses the key.
+func (r *RedisStorage) SetTagged(key string, val []byte, exp time.Duration, tags []string, gen int64) error {
+	keys := append(r.keys(key), r.tagKeys(tags)...)
+	return setTaggedScript.Run(context.Background(), r.client, keys, val, exp.Milliseconds(), gen).Err()
+}
+
+// Invalidate atomically removes every key of the given tags, and the tags themselves.
+func (r *RedisStorage) Invalidate(tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return invalidateScript.Run(context.Background(), r.client, r.tagKeys(tags), r.prefix).Err()
+}
+
+// Generation returns a counter that grows every time one of the given tags is invalidated.
+// The counters are removed by Reset, which makes the sum drop instead.
+func (r *RedisStorage) Generation(tags ...string) (int64, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+	// The keys of the generations follow the keys of the tags
+	vals, err := r.client.MGet(context.Background(), r.tagKeys(tags)[len(tags):]...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var gen int64
+	for _, val := range vals {
+		if val == nil {
+			continue
+		}
+		n, err := strconv.ParseInt(val.(string), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		gen += n
+	}
+	return gen, nil
+}
+
+// keys returns the key of the value stored under key and of the set of its tags.
+func (r *RedisStorage) keys(key string) []string {
+	return []string{r.prefix + key, r.prefix + "tags:" + key}
+}
+
+// tagKeys returns the keys of the sets holding the keys of tags, followed by the keys of
+// the generations of tags.
+func (r *RedisStorage) tagKeys(tags []string) []string {
+	keys := make([]string, 0, 2*len(tags))
+	for _, tag := range tags {
+		keys = append(keys, r.prefix+"tag:"+tag)
+	}
+	for _, tag := range tags {
+		keys = append(keys, r.prefix+"gen:"+tag)
+	}
+	return keys
+}
+
+// Delete removes the value associated with the given key, and the key from its tags.
+func (r *RedisStorage) Delete(key string) error {
+	return deleteScript.Run(context.Background(), r.client, r.keys(key)).Err()
+}
+
+// Reset removes every key under the prefix. Keys are found with SCAN, so the server
+// is never blocked, and deleted in batches once the scan is complete.
+func (r *RedisStorage) Reset() error {
+	ctx := context.Background()
+
+	keys := []string{}
+	iter := r.client.Scan(ctx, 0, r.prefix+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	for len(keys) != 0 {
+		batch := keys[:min(len(keys), 1000)]
+		if err := r.client.Unlink(ctx, batch...).Err(); err != nil {
+			return err
+		}
+		keys = keys[len(batch):]
+	}
+	return nil
+}
+
+// Close does nothing, the client is owned by the caller and may be shared with a RedisMutex.
+func (r *RedisStorage) Close() error {
+	return nil
+}
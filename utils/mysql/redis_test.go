@@ -0,0 +1,226 @@
+package mysql
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRedis starts an in-process Redis server and returns a client connected to it.
+func newRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisStorage(t *testing.T) {
+	server, client := newRedis(t)
+	storage := NewRedisStorage(client, "mint:")
+
+	// Missing keys are not an error
+	val, err := storage.Get("missing")
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+
+	// Keys are namespaced and expire
+	assert.NoError(t, storage.Set("key", []byte("value"), time.Second))
+	assert.True(t, server.Exists("mint:key"))
+	val, err = storage.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+
+	server.FastForward(2 * time.Second)
+	val, err = storage.Get("key")
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+
+	// SetNX only stores absent keys
+	stored, err := storage.SetNX("nonce", []byte{1}, time.Second)
+	assert.NoError(t, err)
+	assert.True(t, stored)
+	stored, err = storage.SetNX("nonce", []byte{2}, time.Second)
+	assert.NoError(t, err)
+	assert.False(t, stored)
+	server.FastForward(2 * time.Second)
+	stored, err = storage.SetNX("nonce", []byte{3}, time.Second)
+	assert.NoError(t, err)
+	assert.True(t, stored)
+
+	// Delete removes a single key
+	assert.NoError(t, storage.Set("key", []byte("value"), 0))
+	assert.NoError(t, storage.Delete("key"))
+	assert.False(t, server.Exists("mint:key"))
+}
+
+func TestRedisStorageReset(t *testing.T) {
+	server, client := newRedis(t)
+	storage := NewRedisStorage(client, "mint:")
+
+	for i := 0; i < 2500; i++ {
+		assert.NoError(t, storage.Set(string(rune('a'+i%26))+time.Duration(i).String(), []byte{1}, 0))
+	}
+	assert.NoError(t, server.Set("other:key", "value"))
+
+	assert.NoError(t, storage.Reset())
+	assert.Equal(t, []string{"other:key"}, server.Keys())
+}
+
+func TestRedisMutex(t *testing.T) {
+	_, client := newRedis(t)
+	mutex := NewRedisMutex(client, "mint:")
+
+	// Locks exclude each other and hand out growing fencing tokens
+	first, err := mutex.Lock("key")
+	assert.NoError(t, err)
+
+	other := NewRedisMutex(client, "mint:")
+	other.Timeout = 50 * time.Millisecond
+	_, err = other.Lock("key")
+	assert.ErrorIs(t, err, ErrLockTimeout)
+
+	assert.NoError(t, mutex.Unlock("key", first))
+
+	second, err := other.Lock("key")
+	assert.NoError(t, err)
+	assert.Greater(t, second, first)
+	assert.NoError(t, other.Unlock("key", second))
+
+	// Unlocking a key that is not locked fails
+	assert.ErrorIs(t, mutex.Unlock("key", second), ErrLockLost)
+}
+
+func TestRedisMutexExpired(t *testing.T) {
+	server, client := newRedis(t)
+	mutex := NewRedisMutex(client, "mint:")
+	mutex.TTL = time.Second
+
+	// Both holders share one mutex, as the goroutines of one process do
+	first, err := mutex.Lock("key")
+	assert.NoError(t, err)
+	server.FastForward(2 * time.Second)
+
+	// Another holder takes the expired lock, which the first one must not release
+	second, err := mutex.Lock("key")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, mutex.Unlock("key", first), ErrLockLost)
+	assert.True(t, server.Exists("mint:lock:key"))
+	assert.NoError(t, mutex.Unlock("key", second))
+	assert.False(t, server.Exists("mint:lock:key"))
+}
+
+func TestRedisMutexRenewed(t *testing.T) {
+	server, client := newRedis(t)
+	mutex := NewRedisMutex(client, "mint:")
+	mutex.TTL = 300 * time.Millisecond
+
+	token, err := mutex.Lock("key")
+	assert.NoError(t, err)
+
+	// The lock is renewed while it is held, so it outlives its TTL
+	server.FastForward(200 * time.Millisecond)
+	time.Sleep(150 * time.Millisecond)
+	server.FastForward(200 * time.Millisecond)
+	assert.True(t, server.Exists("mint:lock:key"))
+	assert.NoError(t, mutex.Unlock("key", token))
+}
+
+func TestRedisMutexConcurrent(t *testing.T) {
+	_, client := newRedis(t)
+	mutex := NewRedisMutex(client, "mint:")
+
+	counter := 0
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := mutex.Lock("key")
+			assert.NoError(t, err)
+			value := counter
+			time.Sleep(time.Millisecond)
+			counter = value + 1
+			assert.NoError(t, mutex.Unlock("key", token))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, counter)
+}
+
+func TestRedisStorageTags(t *testing.T) {
+	server, client := newRedis(t)
+	storage := NewRedisStorage(client, "mint:")
+
+	assert.NoError(t, storage.SetTagged("stats", []byte{1}, time.Minute, []string{"queue"}, 0))
+	assert.NoError(t, storage.SetTagged("payouts", []byte{2}, 2*time.Minute, []string{"queue", "success"}, 0))
+	assert.NoError(t, storage.SetTagged("keys", []byte{3}, time.Minute, []string{"keys"}, 0))
+
+	// A tag lives as long as its longest-lived key
+	assert.Equal(t, 2*time.Minute, server.TTL("mint:tag:queue"))
+
+	assert.NoError(t, storage.Invalidate("queue"))
+	assert.False(t, server.Exists("mint:stats"))
+	assert.False(t, server.Exists("mint:payouts"))
+	assert.False(t, server.Exists("mint:tag:queue"))
+	assert.True(t, server.Exists("mint:keys"))
+}
+
+func TestRedisStorageGeneration(t *testing.T) {
+	server, client := newRedis(t)
+	storage := NewRedisStorage(client, "mint:")
+
+	// A value is not stored once one of its tags was invalidated since its generation was read
+	gen, err := storage.Generation("queue", "success")
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Invalidate("success"))
+	assert.NoError(t, storage.SetTagged("stats", []byte{1}, time.Minute, []string{"queue", "success"}, gen))
+	assert.False(t, server.Exists("mint:stats"))
+	assert.False(t, server.Exists("mint:tag:queue"))
+
+	gen, err = storage.Generation("queue", "success")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), gen)
+	assert.NoError(t, storage.SetTagged("stats", []byte{1}, time.Minute, []string{"queue", "success"}, gen))
+	assert.True(t, server.Exists("mint:stats"))
+}
+
+func TestRedisStorageTagsPruned(t *testing.T) {
+	server, client := newRedis(t)
+	storage := NewRedisStorage(client, "mint:")
+
+	// Invalidating a tag removes its keys from their other tags
+	assert.NoError(t, storage.SetTagged("payouts", []byte{1}, 0, []string{"queue", "success"}, 0))
+	assert.NoError(t, storage.Invalidate("queue"))
+	assert.False(t, server.Exists("mint:tag:success"))
+	assert.False(t, server.Exists("mint:tags:payouts"))
+
+	// Writes to a tag after its invalidation pass its new generation
+	gen, err := storage.Generation("queue")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), gen)
+
+	// A key overwritten without tags or deleted leaves its tags
+	assert.NoError(t, storage.SetTagged("stats", []byte{1}, 0, []string{"queue"}, gen))
+	assert.NoError(t, storage.SetTagged("stats", []byte{2}, 0, []string{"success"}, 0))
+	assert.False(t, server.Exists("mint:tag:queue"))
+	assert.NoError(t, storage.Set("stats", []byte{3}, 0))
+	assert.False(t, server.Exists("mint:tag:success"))
+	assert.NoError(t, storage.SetTagged("stats", []byte{4}, 0, []string{"queue"}, gen))
+	assert.NoError(t, storage.Delete("stats"))
+	assert.False(t, server.Exists("mint:tag:queue"))
+	assert.False(t, server.Exists("mint:tags:stats"))
+
+	// Expired keys are pruned from a tag that never expires as it is written to
+	assert.NoError(t, storage.SetTagged("expiring", []byte{1}, time.Second, []string{"queue"}, gen))
+	assert.NoError(t, storage.SetTagged("forever", []byte{1}, 0, []string{"queue"}, gen))
+	server.FastForward(2 * time.Second)
+	assert.NoError(t, storage.SetTagged("other", []byte{1}, 0, []string{"queue"}, gen))
+	members, err := server.Members("mint:tag:queue")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"mint:forever", "mint:other"}, members)
+}
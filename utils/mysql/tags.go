@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"mint/utils/logger"
+)
+
+// TaggedStorage is a Storage that also tracks which keys belong to which tags, so a
+// write can evict every cached result it makes stale.
+type TaggedStorage interface {
+	Storage
+
+	// SetTagged stores a key-value pair like Set and adds the key to every given tag, unless
+	// one of the tags was invalidated since Generation returned gen for them.
+	SetTagged(key string, val []byte, exp time.Duration, tags []string, gen int64) error
+
+	// Invalidate atomically removes every key of the given tags, and the tags themselves.
+	Invalidate(tags ...string) error
+
+	// Generation returns a counter that grows every time one of the given tags is invalidated.
+	Generation(tags ...string) (int64, error)
+}
+
+// generation returns the generation of the tags of a query before it runs, so its result
+// is not cached if a write invalidates them while it runs. It reports false when the
+// generation cannot be read, the result must then not be cached.
+func (c *CoreEntity) generation(params Params) (int64, bool) {
+	if !c.CacheEnabled || params.CacheDelay <= 0 || len(params.Tags) == 0 {
+		return 0, true
+	}
+	tagged, ok := c.cache.(TaggedStorage)
+	if !ok {
+		return 0, true
+	}
+	gen, err := tagged.Generation(params.Tags...)
+	return gen, err == nil
+}
+
+// store caches the result of a query under key for exp, adding it to the given tags when
+// the storage tracks them. A result whose tags were invalidated since gen is not stored.
+func (c *CoreEntity) store(key string, val []byte, exp time.Duration, tags []string, gen int64) error {
+	if tagged, ok := c.cache.(TaggedStorage); ok && len(tags) != 0 {
+		return tagged.SetTagged(key, val, exp, tags, gen)
+	}
+	return c.cache.Set(key, val, exp)
+}
+
+// invalidate evicts the cached results of the given tags after a successful write.
+// A storage that does not track tags is reset entirely, which is never stale.
+func (c *CoreEntity) invalidate(tags []string) error {
+	if !c.CacheEnabled || len(tags) == 0 {
+		return nil
+	}
+	if tagged, ok := c.cache.(TaggedStorage); ok {
+		return tagged.Invalidate(tags...)
+	}
+	return c.cache.Reset()
+}
+
+// evict invalidates the given tags and logs a failure through the logger of ctx. The write
+// has already succeeded, so the failure is not returned, but the results of the tags may
+// then be served stale until they expire.
+func (c *CoreEntity) evict(ctx context.Context, tags []string) {
+	if err := c.invalidate(tags); err != nil {
+		logger.FromContext(ctx).Error("failed to invalidate cached results", "tags", tags, "error", err)
+	}
+}
@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// keyExec is the attribute naming the stored procedure of a span.
+const keyExec = attribute.Key("db.operation")
+
+// tracer starts the spans of this package with the global tracer provider, which produces
+// no-op spans until the application installs one.
+var tracer = otel.Tracer("mint/utils/mysql")
+
+// startSpan starts a span only as the child of a span carried by ctx. Without one it
+// returns ctx and a non-recording span, so that polls running every tick, mostly on
+// empty tables, do not each start a trace of their own.
+func startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return tracer.Start(ctx, name, opts...)
+}
+
+// fail records err on span and marks the span as failed. A nil err is ignored.
+func fail(span trace.Span, err *MySQLError) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TestExecSpan checks that a statement is traced under the span of its caller only, and
+// that a failure is recorded on its span.
+func TestExecSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	db, mock, mockErr := sqlmock.New()
+	assert.NoError(t, mockErr)
+	defer db.Close()
+
+	c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+	prepare := mock.ExpectPrepare(`CALL USER_DELETE\(\?\)`)
+	prepare.ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectExec().WithArgs(1).WillReturnError(&mysql.MySQLError{Number: 1213})
+
+	// Without a parent no span is recorded
+	_, err := ExecContext(context.Background(), c, Params{Exec: "USER_DELETE", Args: []any{1}})
+	assert.Nil(t, err)
+	assert.Empty(t, recorder.Ended())
+
+	parent, root := provider.Tracer("test").Start(context.Background(), "request")
+	_, err = ExecContext(parent, c, Params{Exec: "USER_DELETE", Args: []any{1}})
+	root.End()
+	assert.ErrorIs(t, err, ErrDeadlock)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "mysql USER_DELETE", spans[0].Name())
+	assert.Equal(t, root.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), keyExec.String("USER_DELETE"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,260 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Retries of a transaction after a deadlock when TxOptions leaves them zero.
+const (
+	DefaultRetries = 3                     // Number of retries
+	DefaultBackoff = 20 * time.Millisecond // Delay before the first retry
+)
+
+// TxOptions defines how a transaction is started and retried.
+type TxOptions struct {
+	Isolation sql.IsolationLevel // Isolation level, the server default if zero
+	ReadOnly  bool               // Whether the transaction only reads
+	Timeout   time.Duration      // Upper bound for the whole transaction, 100 seconds if zero
+	Retries   int                // Number of retries after a deadlock, DefaultRetries if zero, none if negative
+	Backoff   time.Duration      // Delay before the first retry, DefaultBackoff if zero, doubled on every next one
+}
+
+// Tx is a database transaction started by Begin. Queries run through QueryTx and Exec
+// use the connection of the transaction, so they see each other's changes.
+type Tx struct {
+	c          *CoreEntity     // The entity the transaction was started on
+	tx         *sql.Tx         // The underlying SQL transaction
+	ctx        context.Context // The context bounding the whole transaction
+	invalidate []string        // Tags to invalidate once the transaction is committed
+}
+
+// Begin starts a transaction with the given options. The caller must call Commit or
+// Rollback, and cancel to release the context of the transaction.
+func (c *CoreEntity) Begin(opt TxOptions) (*Tx, context.CancelFunc, *MySQLError) {
+	return c.BeginContext(context.Background(), opt)
+}
+
+// BeginContext is Begin bounded by parent: the transaction is rolled back when parent
+// is canceled, and opt.Timeout still applies as an upper bound.
+func (c *CoreEntity) BeginContext(parent context.Context, opt TxOptions) (*Tx, context.CancelFunc, *MySQLError) {
+	ctx, cancel := createContextWithTimeout(parent, opt.Timeout)
+
+	tx, err := c.DB.BeginTx(ctx, &sql.TxOptions{
+		Isolation: opt.Isolation,
+		ReadOnly:  opt.ReadOnly,
+	})
+	if err != nil {
+		cancel()
+		return nil, nil, convertError(err)
+	}
+
+	return &Tx{c: c, tx: tx, ctx: ctx}, cancel, nil
+}
+
+// Commit commits the transaction, then evicts the cached results its statements made stale.
+func (t *Tx) Commit() *MySQLError {
+	if err := t.tx.Commit(); err != nil {
+		return convertError(err)
+	}
+	t.c.evict(t.ctx, t.invalidate)
+	return nil
+}
+
+// Rollback aborts the transaction. Rolling back a finished transaction is a no-op.
+func (t *Tx) Rollback() *MySQLError {
+	if err := t.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return convertError(err)
+	}
+	return nil
+}
+
+// Exec executes a statement within the transaction that returns no rows.
+func (t *Tx) Exec(params Params) (*Result, *MySQLError) {
+	label := labelOf(params)
+	start := time.Now()
+
+	ctx, span, cancel := t.start(label, params.Timeout)
+	defer span.End()
+	defer cancel()
+
+	// Evicting more than needed is harmless, so the tags are kept even if the statement fails
+	t.invalidate = append(t.invalidate, params.Invalidate...)
+
+	if params.Query == "" && hasOut(params.Args) {
+		res, sqlErr := callExec(ctx, t.tx, params)
+		if sqlErr != nil {
+			fail(span, sqlErr)
+		}
+		t.c.observe(label, start, sqlErr)
+		return res, sqlErr
+	}
+
+	res, err := t.tx.ExecContext(ctx, statement(params), params.Args...)
+	if err != nil {
+		sqlErr := convertContextError(ctx, err)
+		fail(span, sqlErr)
+		t.c.observe(label, start, sqlErr)
+		return nil, sqlErr
+	}
+	t.c.observe(label, start, nil)
+	return newResult(res), nil
+}
+
+// QueryTx executes a query within the transaction and returns the result via a callback
+// function, like Query. Results are never cached since they may not be committed yet.
+func QueryTx[T any](
+	t *Tx,
+	params Params,
+	callback func(rows *sql.Rows) (*T, *MySQLError),
+) (*T, *MySQLError) {
+	label := labelOf(params)
+	start := time.Now()
+
+	ctx, span, cancel := t.start(label, params.Timeout)
+	defer span.End()
+	defer cancel()
+
+	// Evicting more than needed is harmless, so the tags are kept even if the statement fails
+	t.invalidate = append(t.invalidate, params.Invalidate...)
+
+	if params.Query == "" && hasOut(params.Args) {
+		res, sqlErr := callRows(ctx, t.tx, params, callback)
+		if sqlErr != nil {
+			fail(span, sqlErr)
+		}
+		t.c.observe(label, start, sqlErr)
+		return res, sqlErr
+	}
+
+	rows, err := t.tx.QueryContext(ctx, statement(params), params.Args...)
+	if err != nil {
+		sqlErr := convertContextError(ctx, err)
+		fail(span, sqlErr)
+		t.c.observe(label, start, sqlErr)
+		return nil, sqlErr
+	}
+	defer rows.Close()
+
+	res, sqlErr := callback(rows)
+	if sqlErr != nil {
+		fail(span, sqlErr)
+	}
+	t.c.observe(label, start, sqlErr)
+	return res, sqlErr
+}
+
+// Transaction runs fn within a transaction and commits it if fn succeeds. When fn or the
+// commit fails with a deadlock, the transaction is rolled back and fn runs again, up to
+// opt.Retries times with exponential backoff. Any other error rolls the transaction back.
+func Transaction[T any](
+	c *CoreEntity,
+	opt TxOptions,
+	fn func(tx *Tx) (*T, *MySQLError),
+) (*T, *MySQLError) {
+	return TransactionContext(context.Background(), c, opt, fn)
+}
+
+// TransactionContext is Transaction bounded by ctx. No retry is attempted once ctx is done.
+func TransactionContext[T any](
+	ctx context.Context,
+	c *CoreEntity,
+	opt TxOptions,
+	fn func(tx *Tx) (*T, *MySQLError),
+) (*T, *MySQLError) {
+	ctx, span := startSpan(ctx, "mysql transaction",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "mysql")),
+	)
+	defer span.End()
+
+	retries, delay := opt.Retries, opt.Backoff
+	if retries == 0 {
+		retries = DefaultRetries
+	}
+	if delay <= 0 {
+		delay = DefaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := transaction(ctx, c, opt, fn)
+		if err == nil || !IsDeadlock(err) || attempt >= retries || ctx.Err() != nil {
+			if err != nil {
+				fail(span, err)
+			}
+			return res, err
+		}
+
+		span.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", attempt+1)))
+		select {
+		case <-ctx.Done():
+		case <-time.After(jitter(delay)):
+		}
+		delay *= 2
+	}
+}
+
+// jitter returns a random delay between half and one and a half times delay, so the
+// transactions that deadlocked each other do not retry at the same time again.
+func jitter(delay time.Duration) time.Duration {
+	return delay/2 + rand.N(delay)
+}
+
+// transaction implements a single attempt of Transaction.
+func transaction[T any](
+	ctx context.Context,
+	c *CoreEntity,
+	opt TxOptions,
+	fn func(tx *Tx) (*T, *MySQLError),
+) (*T, *MySQLError) {
+	tx, cancel, err := c.BeginContext(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	res, err := fn(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	return res, nil
+}
+
+// IsDeadlock reports whether err is a deadlock. Unlike errors.Is with ErrDeadlock, it also
+// recognizes errors built by hand from the server number or the custom message.
+func IsDeadlock(err *MySQLError) bool {
+	return err != nil && (errors.Is(err, ErrDeadlock) ||
+		err.Number == numberDeadlock ||
+		(err.Number == 45000 && err.Message == "DEADLOCK"))
+}
+
+// start opens the span of a single statement and derives its context, bounded by both
+// the transaction and the timeout of the statement.
+func (t *Tx) start(label string, timeout time.Duration) (context.Context, trace.Span, context.CancelFunc) {
+	ctx, span := startSpan(t.ctx, "mysql "+label,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			keyExec.String(label),
+		),
+	)
+
+	if timeout == 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, span, cancel
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, span, cancel
+}
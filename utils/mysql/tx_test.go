@@ -0,0 +1,165 @@
+package mysql
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTransaction contains multiple test cases for the Transaction function.
+func TestTransaction(t *testing.T) {
+	// Test case: Queries and statements share the transaction, which is committed
+	t.Run("Commit", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, name FROM users WHERE id = ?").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"))
+		mock.ExpectExec(`CALL USER_CLAIM\(\?\)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		result, err := Transaction(c, TxOptions{Isolation: sql.LevelSerializable}, func(tx *Tx) (*User, *MySQLError) {
+			user, err := QueryTx(tx, Params{
+				Query: "SELECT id, name FROM users WHERE id = ?",
+				Args:  []any{1},
+			}, func(rows *sql.Rows) (*User, *MySQLError) {
+				var data User
+				if rows.Next() {
+					_ = rows.Scan(&data.ID, &data.Name)
+				}
+				return &data, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			res, err := tx.Exec(Params{Exec: "USER_CLAIM", Args: []any{user.ID}})
+			if err != nil {
+				return nil, err
+			}
+			assert.Equal(t, int64(1), res.RowsAffected)
+			return user, nil
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, User{ID: 1, Name: "John Doe"}, *result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Test case: An error of the callback rolls the transaction back
+	t.Run("Rollback", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`CALL USER_CLAIM\(\?\)`).WithArgs(1).
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		mock.ExpectRollback()
+
+		result, err := Transaction(c, TxOptions{Retries: 3}, func(tx *Tx) (*bool, *MySQLError) {
+			_, err := tx.Exec(Params{Exec: "USER_CLAIM", Args: []any{1}})
+			return nil, err
+		})
+
+		assert.Nil(t, result)
+		assert.Equal(t, uint16(1062), err.Number)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Test case: A deadlock is retried in a new transaction
+	t.Run("Deadlock Retry", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`CALL USER_CLAIM\(\?\)`).WithArgs(1).
+			WillReturnError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"})
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec(`CALL USER_CLAIM\(\?\)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		attempts := 0
+		_, err := Transaction(c, TxOptions{Retries: 1, Backoff: time.Millisecond}, func(tx *Tx) (*bool, *MySQLError) {
+			attempts++
+			_, err := tx.Exec(Params{Exec: "USER_CLAIM", Args: []any{1}})
+			return nil, err
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Test case: The deadlock is returned once the retries are exhausted
+	t.Run("Deadlock Exhausted", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"})
+
+		_, err := Transaction(c, TxOptions{Retries: -1}, func(tx *Tx) (*bool, *MySQLError) {
+			return nil, nil
+		})
+
+		assert.True(t, IsDeadlock(err))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Test case: Zero options retry a deadlock DefaultRetries times
+	t.Run("Deadlock Default Retries", func(t *testing.T) {
+		db, mock, mockErr := sqlmock.New()
+		assert.NoError(t, mockErr)
+		defer db.Close()
+
+		c := &CoreEntity{DB: db, prepare: make(map[string]*sql.Stmt)}
+
+		for i := 0; i <= DefaultRetries; i++ {
+			mock.ExpectBegin()
+			mock.ExpectCommit().WillReturnError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"})
+		}
+
+		attempts := 0
+		_, err := Transaction(c, TxOptions{}, func(tx *Tx) (*bool, *MySQLError) {
+			attempts++
+			return nil, nil
+		})
+
+		assert.True(t, IsDeadlock(err))
+		assert.Equal(t, DefaultRetries+1, attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		delay := jitter(10 * time.Millisecond)
+		assert.GreaterOrEqual(t, delay, 5*time.Millisecond)
+		assert.Less(t, delay, 15*time.Millisecond)
+	}
+}
+
+func TestIsDeadlock(t *testing.T) {
+	assert.True(t, IsDeadlock(&MySQLError{Number: 1213}))
+	assert.True(t, IsDeadlock(&MySQLError{Number: 45000, Message: "DEADLOCK"}))
+	assert.False(t, IsDeadlock(&MySQLError{Number: 45000, Message: "TIMEOUT"}))
+	assert.False(t, IsDeadlock(nil))
+}
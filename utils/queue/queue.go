@@ -2,99 +2,243 @@ package queue
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"mint/config"
 	"mint/shared/models"
 	"mint/storage"
+	"mint/utils/limits"
+	"mint/utils/logger"
+	"mint/utils/metrics"
+	"mint/utils/screening"
+	"mint/utils/tracing"
 	"mint/utils/wallet"
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Sheldule runs the payout worker forever, sending one batch per iteration.
+// Consecutive failures are retried with an exponential backoff.
 func Sheldule() {
 
-	defer func() {
+	failures := 0
+	for {
 
-		if r := recover(); r != nil {
-			log.Println("Recovered from panic:", r) // Log the panic information.
+		if paused.Load() {
+			state.Store(StatePaused)
+			time.Sleep(interval)
+			continue
 		}
 
-		time.Sleep(time.Second)
-		Sheldule()
+		state.Store(StateRunning)
+		if err := safely(sheldule); err != nil {
+			failures++
+			state.Store(StateBackingOff)
+			slog.Error("worker recovered from panic", "panic", err, "failures", failures)
+			time.Sleep(backoff(failures))
+			continue
+		}
 
-	}()
+		failures = 0
+		time.Sleep(interval)
+	}
+
+}
 
-	transaction, errSQL := storage.QUEUE_GET(3)
+// sheldule sends a single batch of queued payouts.
+func sheldule() {
+
+	// Every log line of this iteration carries the batch identifier
+	batchID := logger.NewID()
+	log := slog.Default().With(logger.KeyBatchID, batchID)
+	ctx := logger.WithContext(context.Background(), log)
+
+	transaction, errSQL := storage.QUEUE_GET_CONTEXT(ctx, 3)
 	if errSQL != nil {
 		panic(errSQL)
 	}
+	if len(*transaction) == 0 {
+		return
+	}
+
+	// The batch span starts a new trace linked to the intake span of every payout in it
+	traces := []string{}
+	for _, i := range *transaction {
+		traces = append(traces, i.Trace)
+	}
+	ctx, span := tracing.Tracer.Start(ctx, "queue.batch",
+		trace.WithNewRoot(),
+		trace.WithLinks(tracing.Links(traces...)...),
+		trace.WithAttributes(tracing.KeyBatchID.String(batchID)),
+	)
+	defer span.End()
 
-	messages := []wallet.Transaction{}
+	// Re-check the limits against what was actually sent, including the payouts of this batch
+	var (
+		batch    = []models.Queue{}
+		messages = []wallet.Transaction{}
+		pending  = limits.Totals{}
+		wallets  = map[string]int64{}
+	)
 	for _, i := range *transaction {
+		log := log.With(logger.KeyTransaction, i.Transaction)
+
+		// Screen again, the lists may have changed since the payout was accepted
+		if screen := screening.Default.Check(i.Wallet); screen.Denied() {
+			log.Warn("payout blocked by screening", "list", screen.List)
+			if _, errSQL = storage.SCREENING_ADD_CONTEXT(ctx, i.Transaction, i.Wallet, screen.Outcome, screen.List); errSQL != nil {
+				panic(errSQL)
+			}
+			if _, errSQL = storage.QUEUE_BLOCK_CONTEXT(ctx, i.Transaction, screen.List, "screening"); errSQL != nil {
+				panic(errSQL)
+			}
+			continue
+		}
+
+		// Payouts approved by a second person have already been reviewed against the limits
+		if len(i.ApprovedBy) == 0 {
+			totals, errSQL := limits.Default.Load(ctx, config.WalletJetton, i.Wallet, true)
+			if errSQL != nil {
+				panic(errSQL)
+			}
+			// Wallets are stored in their canonical form, so one map key covers every spelling
+			totals.Wallet += wallets[i.Wallet]
+			totals.Hourly += pending.Hourly
+			totals.Daily += pending.Daily
+
+			if reason := limits.Default.Evaluate(int64(i.Amount), totals); len(reason) != 0 {
+				log.Warn("payout moved to approval", "reason", reason)
+				if _, errSQL = storage.QUEUE_REVIEW_CONTEXT(ctx, i.Transaction, reason, "limits"); errSQL != nil {
+					panic(errSQL)
+				}
+				continue
+			}
+		}
+
+		wallets[i.Wallet] += int64(i.Amount)
+		pending.Hourly += int64(i.Amount)
+		pending.Daily += int64(i.Amount)
+
+		batch = append(batch, i)
 		messages = append(messages, wallet.Transaction{
-			Wallet:  i.Wallet,
-			Amount:  uint64(i.Amount),
-			Message: i.Message,
+			Transaction: i.Transaction,
+			Wallet:      i.Wallet,
+			Amount:      uint64(i.Amount),
+			Message:     i.Message,
 		})
 	}
 
 	if len(messages) == 0 {
 		return
 	}
+	span.SetAttributes(tracing.KeyBatchSize.Int(len(messages)))
+
+	metrics.Payouts.WithLabelValues(config.WalletJetton, "sent").Add(float64(len(batch)))
+	start := time.Now()
 
 	txHash, err := wallet.Core.Withdraw(
+		ctx,
 		config.WalletJetton,      // Jetton wallet address
 		config.WalletDestination, // Source wallet address (from which to withdraw)
 		messages,
 	)
 
+	metrics.WalletSendDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
+		metrics.Payouts.WithLabelValues(config.WalletJetton, "failed").Add(float64(len(batch)))
+		tracing.Fail(span, err)
 		panic(err)
 	}
+	metrics.Payouts.WithLabelValues(config.WalletJetton, "confirmed").Add(float64(len(batch)))
 
-	for _, i := range *transaction {
-		_, errSQL = storage.QUEUE_SUCCESS(i.Transaction, txHash)
+	for _, i := range batch {
+		_, errSQL = storage.QUEUE_SUCCESS_CONTEXT(ctx, i.Transaction, txHash)
 		if errSQL != nil {
 			panic(errSQL)
 		}
+		log.Info("payout sent", logger.KeyTransaction, i.Transaction, "hash", txHash)
 	}
 
 }
 
+// Callback delivers the results of sent payouts to CallbackURL forever.
 func Callback() {
 
-	defer func() {
+	failures := 0
+	for {
 
-		if r := recover(); r != nil {
-			log.Println("Recovered from panic:", r) // Log the panic information.
+		if err := safely(callback); err != nil {
+			failures++
+			slog.Error("callback worker recovered from panic", "panic", err, "failures", failures)
+			time.Sleep(backoff(failures))
+			continue
 		}
 
-		time.Sleep(time.Second)
-		Sheldule()
+		failures = 0
+		time.Sleep(interval)
+	}
 
-	}()
+}
+
+// callback delivers a single batch of undelivered results.
+func callback() {
 
-	transaction, errSQL := storage.SUCCESS_GET(10)
+	transaction, errSQL := storage.SUCCESS_GET_CONTEXT(context.Background(), 10)
 	if errSQL != nil {
 		panic(errSQL)
 	}
 
 	for _, item := range transaction {
-		res, err := PostSuccessAndCheckOK(config.CallbackURL, item, time.Second*5)
+		log := slog.Default().With(logger.KeyTransaction, item.Transaction)
+
+		// Every delivery starts a new trace linked to the intake span of the payout
+		ctx, span := tracing.Tracer.Start(context.Background(), "callback.deliver",
+			trace.WithNewRoot(),
+			trace.WithLinks(tracing.Links(item.Trace)...),
+			trace.WithAttributes(tracing.KeyTransaction.String(item.Transaction)),
+		)
+
+		metrics.CallbackAttempts.Inc()
+		res, err := PostSuccessAndCheckOK(ctx, config.CallbackURL, item, time.Second*5)
+		span.End()
+		if err != nil || !res {
+			metrics.CallbackFailures.Inc()
+			log.Warn("callback not delivered", "error", err)
+		}
 		if err != nil {
 			continue
 		}
 
 		if res {
-			storage.SUCCESS_DELETE(item.Hash)
+			storage.SUCCESS_DELIVERED_CONTEXT(ctx, item.Transaction)
+			log.Info("callback delivered")
 		}
 	}
 
 }
 
-func PostSuccessAndCheckOK(url string, success *models.Success, timeout time.Duration) (bool, error) {
+// PostSuccessAndCheckOK posts success to url in a client span of ctx, propagating
+// the trace in the "traceparent" header, and reports whether the receiver answered "OK".
+func PostSuccessAndCheckOK(ctx context.Context, url string, success *models.Success, timeout time.Duration) (ok bool, err error) {
+	ctx, span := tracing.Tracer.Start(ctx, "POST callback",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(tracing.KeyTransaction.String(success.Transaction)),
+	)
+	defer func() {
+		tracing.Fail(span, err)
+		if err == nil && !ok {
+			span.SetStatus(codes.Error, "callback rejected")
+		}
+		span.End()
+	}()
+
 	// Serialize struct to JSON
 	body, err := json.Marshal(success)
 	if err != nil {
@@ -105,11 +249,19 @@ func PostSuccessAndCheckOK(url string, success *models.Success, timeout time.Dur
 		Timeout: timeout,
 	}
 
-	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	tracing.InjectHeader(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return false, err // Error occurred while sending the request
 	}
 	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	// Read the response body
 	responseBody, err := ioutil.ReadAll(resp.Body)
@@ -0,0 +1,47 @@
+package queue
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mint/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestPostSuccessAndCheckOK(t *testing.T) {
+	var traceparent, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "callback.deliver")
+	defer span.End()
+
+	ok, err := PostSuccessAndCheckOK(ctx, server.URL, &models.Success{Transaction: "tx-1", Trace: "hidden"}, time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Contains(t, body, `"transaction":"tx-1"`)
+	assert.NotContains(t, body, "hidden")
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+}
+
+func TestPostSuccessAndCheckOKRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ok, err := PostSuccessAndCheckOK(context.Background(), server.URL, &models.Success{}, time.Second)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,69 @@
+package queue
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// States of the payout worker reported by State.
+const (
+	StateStarting   = "starting"    // Sheldule has not been started yet
+	StateRunning    = "running"     // The worker is sending payouts
+	StatePaused     = "paused"      // The worker is paused and sends nothing
+	StateBackingOff = "backing_off" // The last iteration failed and the worker waits before retrying
+)
+
+// Delays between worker iterations.
+const (
+	interval   = time.Second // Delay after a successful iteration
+	maxBackoff = time.Minute // Upper bound of the delay after consecutive failures
+)
+
+var (
+	state  atomic.Value // Current state of the payout worker
+	paused atomic.Bool  // Whether the payout worker must stay idle
+)
+
+func init() {
+	state.Store(StateStarting)
+}
+
+// State returns the current state of the payout worker.
+func State() string {
+	return state.Load().(string)
+}
+
+// Pause stops the payout worker after the current iteration.
+func Pause() {
+	paused.Store(true)
+}
+
+// Resume lets a paused payout worker continue sending.
+func Resume() {
+	paused.Store(false)
+}
+
+// backoff returns the delay after the given number of consecutive failures,
+// doubling from interval up to maxBackoff.
+func backoff(failures int) time.Duration {
+	delay := interval
+	for i := 1; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// safely runs fn and converts a panic into an error.
+func safely(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	fn()
+	return nil
+}
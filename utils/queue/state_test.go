@@ -0,0 +1,26 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(0))
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, 2*time.Second, backoff(2))
+	assert.Equal(t, 4*time.Second, backoff(3))
+	assert.Equal(t, time.Minute, backoff(100))
+}
+
+func TestSafely(t *testing.T) {
+	assert.NoError(t, safely(func() {}))
+	assert.EqualError(t, safely(func() { panic(errors.New("boom")) }), "boom")
+}
+
+func TestState(t *testing.T) {
+	assert.Equal(t, StateStarting, State())
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// maxIdle is the number of buckets above which idle buckets are forgotten.
+const maxIdle = 10000
+
+// Memory keeps the buckets in the memory of the process. Every instance of the
+// service limits on its own.
+type Memory struct {
+	buckets map[string]*Bucket
+	mx      sync.Mutex
+	now     func() time.Time
+}
+
+// NewMemory creates an empty Memory backend.
+func NewMemory() *Memory {
+	return &Memory{
+		buckets: make(map[string]*Bucket),
+		now:     time.Now,
+	}
+}
+
+// Take implements Backend.
+func (m *Memory) Take(key string, policy Policy) (bool, time.Duration, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	now := m.now()
+	if len(m.buckets) > maxIdle {
+		m.forget(now, policy.ttl())
+	}
+
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &Bucket{}
+		m.buckets[key] = bucket
+	}
+
+	allowed, wait := bucket.take(now, policy)
+	return allowed, wait, nil
+}
+
+// forget drops the buckets that have not been used for ttl and are therefore full again.
+func (m *Memory) forget(now time.Time, ttl time.Duration) {
+	for key, bucket := range m.buckets {
+		if now.Sub(bucket.Updated) > ttl {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Backend stores the token buckets of a Limiter.
+type Backend interface {
+	// Take removes one token from the bucket of key, refilled by the given policy,
+	// and returns how long to wait for the next token when the bucket is empty.
+	Take(key string, policy Policy) (bool, time.Duration, error)
+}
+
+// Policy describes a token bucket: Requests tokens are added every Period,
+// and at most Burst tokens are kept. Requests of 0 disables the bucket.
+type Policy struct {
+	Requests int           // Tokens added every Period
+	Period   time.Duration // Refill period
+	Burst    int           // Capacity of the bucket, defaults to Requests
+}
+
+// Enabled reports whether the policy limits anything.
+func (p Policy) Enabled() bool {
+	return p.Requests > 0 && p.Period > 0
+}
+
+// capacity returns the maximum number of tokens in the bucket.
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 {
+		return float64(p.Burst)
+	}
+	return float64(p.Requests)
+}
+
+// rate returns the number of tokens added per second.
+func (p Policy) rate() float64 {
+	return float64(p.Requests) / p.Period.Seconds()
+}
+
+// ttl returns how long an idle bucket takes to refill completely, after which it can be forgotten.
+func (p Policy) ttl() time.Duration {
+	return time.Duration(p.capacity()/p.rate()*float64(time.Second)) + time.Second
+}
+
+// Bucket is the state of a single token bucket.
+type Bucket struct {
+	Tokens  float64   `json:"tokens"`  // Tokens left at Updated
+	Updated time.Time `json:"updated"` // Time of the last take
+}
+
+// take refills the bucket up to now and removes one token if available.
+// It returns how long to wait for the next token otherwise.
+func (b *Bucket) take(now time.Time, policy Policy) (bool, time.Duration) {
+	capacity := policy.capacity()
+
+	if b.Updated.IsZero() {
+		b.Tokens = capacity
+	} else if elapsed := now.Sub(b.Updated).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed*policy.rate())
+	}
+	b.Updated = now
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.Tokens) / policy.rate() * float64(time.Second))
+	return false, wait
+}
+
+// Limiter applies a Policy to many keys, keeping the buckets in a Backend.
+type Limiter struct {
+	policy  Policy
+	backend Backend
+}
+
+// New creates a Limiter applying policy with buckets kept in backend.
+func New(policy Policy, backend Backend) *Limiter {
+	return &Limiter{
+		policy:  policy,
+		backend: backend,
+	}
+}
+
+// Allow takes a token for key. When the bucket is empty it returns false and
+// how long to wait before retrying. A disabled policy allows everything.
+func (l *Limiter) Allow(key string) (bool, time.Duration, error) {
+	if l == nil || !l.policy.Enabled() {
+		return true, 0, nil
+	}
+	return l.backend.Take(key, l.policy)
+}
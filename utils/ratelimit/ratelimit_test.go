@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is a manually advanced time source.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestMemory(t *testing.T) {
+	c := &clock{now: time.Unix(1700000000, 0)}
+	backend := NewMemory()
+	backend.now = c.Now
+
+	limiter := New(Policy{Requests: 2, Period: time.Second}, backend)
+	testLimiter(t, limiter, func(d time.Duration) { c.now = c.now.Add(d) })
+}
+
+func newRedis(t *testing.T) (*miniredis.Miniredis, *Redis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, NewRedis(client, "mint:ratelimit:")
+}
+
+func TestRedis(t *testing.T) {
+	server, backend := newRedis(t)
+
+	// The buckets are refilled by the time of the server
+	now := time.Unix(1700000000, 0)
+	server.SetTime(now)
+
+	limiter := New(Policy{Requests: 2, Period: time.Second}, backend)
+	testLimiter(t, limiter, func(d time.Duration) {
+		now = now.Add(d)
+		server.SetTime(now)
+	})
+
+	// An idle bucket is forgotten once it would be full again
+	assert.True(t, server.Exists("mint:ratelimit:a"))
+	server.FastForward(time.Minute)
+	assert.False(t, server.Exists("mint:ratelimit:a"))
+}
+
+func TestRedisConcurrent(t *testing.T) {
+	_, backend := newRedis(t)
+	limiter := New(Policy{Requests: 10, Period: time.Hour}, backend)
+
+	// Concurrent requests never spend the same token
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int32
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, _, err := limiter.Allow("a")
+			assert.NoError(t, err)
+			if ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(10), allowed.Load())
+}
+
+func testLimiter(t *testing.T, limiter *Limiter, advance func(time.Duration)) {
+	// The bucket starts full with two tokens
+	for i := 0; i < 2; i++ {
+		ok, _, err := limiter.Allow("a")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	ok, wait, err := limiter.Allow("a")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Other keys have their own bucket
+	ok, _, _ = limiter.Allow("b")
+	assert.True(t, ok)
+
+	// One token is added every half second
+	advance(500 * time.Millisecond)
+	ok, _, _ = limiter.Allow("a")
+	assert.True(t, ok)
+	ok, _, _ = limiter.Allow("a")
+	assert.False(t, ok)
+
+	// The bucket never holds more than its capacity
+	advance(time.Hour)
+	for i := 0; i < 2; i++ {
+		ok, _, _ = limiter.Allow("a")
+		assert.True(t, ok)
+	}
+	ok, _, _ = limiter.Allow("a")
+	assert.False(t, ok)
+}
+
+func TestBurst(t *testing.T) {
+	backend := NewMemory()
+	limiter := New(Policy{Requests: 1, Period: time.Minute, Burst: 3}, backend)
+
+	for i := 0; i < 3; i++ {
+		ok, _, _ := limiter.Allow("a")
+		assert.True(t, ok)
+	}
+	ok, wait, _ := limiter.Allow("a")
+	assert.False(t, ok)
+	assert.InDelta(t, time.Minute, wait, float64(time.Second))
+}
+
+func TestDisabled(t *testing.T) {
+	limiter := New(Policy{}, NewMemory())
+	for i := 0; i < 100; i++ {
+		ok, _, err := limiter.Allow("a")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	var missing *Limiter
+	ok, _, _ := missing.Allow("a")
+	assert.True(t, ok)
+}
+
+func TestForget(t *testing.T) {
+	c := &clock{now: time.Unix(1700000000, 0)}
+	backend := NewMemory()
+	backend.now = c.Now
+	policy := Policy{Requests: 1, Period: time.Second}
+
+	backend.Take("a", policy)
+	c.now = c.now.Add(time.Minute)
+	backend.forget(c.now, policy.ttl())
+	assert.Empty(t, backend.buckets)
+}
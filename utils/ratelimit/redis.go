@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills the bucket in KEYS[1] up to the time of the server and removes one
+// token if available, in a single step, so concurrent requests of every instance never
+// spend the same token. ARGV holds the capacity, the tokens added per second and the TTL
+// of an idle bucket in milliseconds. It returns whether a token was taken and, otherwise,
+// how many microseconds to wait for the next one.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(bucket[1])
+if tokens == nil then
+	tokens = capacity
+else
+	local elapsed = (now - tonumber(bucket[2])) / 1000000
+	if elapsed > 0 then
+		tokens = math.min(capacity, tokens + elapsed * rate)
+	end
+end
+
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000000)
+end
+
+redis.call("HSET", KEYS[1], "tokens", string.format("%.17g", tokens), "updated", string.format("%.0f", now))
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+return {allowed, wait}
+`)
+
+// Redis keeps the buckets on a Redis server, so every instance of the service limits
+// together. Keys are namespaced by a prefix, and the time of the server refills them.
+type Redis struct {
+	client *redis.Client // The client of the Redis server, owned by the caller
+	prefix string        // The prefix of every bucket
+}
+
+// NewRedis creates a Redis backend on top of client, storing buckets under prefix.
+func NewRedis(client *redis.Client, prefix string) *Redis {
+	return &Redis{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Take implements Backend.
+func (r *Redis) Take(key string, policy Policy) (bool, time.Duration, error) {
+	res, err := takeScript.Run(context.Background(), r.client, []string{r.prefix + key},
+		policy.capacity(), policy.rate(), policy.ttl().Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Microsecond, nil
+}
@@ -0,0 +1,218 @@
+package screening
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"mint/config"
+	"mint/storage"
+
+	"github.com/xssnick/tonutils-go/address"
+)
+
+// Outcomes of screening a destination address.
+const (
+	OutcomePassed = "passed"
+	OutcomeDenied = "denied"
+)
+
+// Kinds of screening lists.
+const (
+	KindDeny  = "deny"
+	KindAllow = "allow"
+)
+
+// Names of the built-in lists reported in a Result.
+const (
+	ListProtected = "protected" // Our own wallets, see Protect
+	ListAllowlist = "allowlist" // The address is missing from a loaded allowlist
+)
+
+// Result describes the outcome of screening a single address.
+type Result struct {
+	Outcome string // OutcomePassed or OutcomeDenied
+	List    string // Name of the list that denied the address, empty when passed
+}
+
+// Denied reports whether the address must not receive payouts.
+func (r Result) Denied() bool {
+	return r.Outcome == OutcomeDenied
+}
+
+// Screener checks destinations against locally loaded allow and deny lists.
+// Lists are loaded from files and optionally the database, and can be reloaded at any time.
+type Screener struct {
+	mu        sync.RWMutex
+	deny      map[string]string   // Normalized address to the name of the denying list
+	allow     map[string]struct{} // Normalized allowed addresses, empty when no allowlist is loaded
+	protected map[string]struct{} // Normalized addresses of our own wallets
+
+	DenylistFiles  []string // Files with denied addresses, one per line
+	AllowlistFiles []string // Files with allowed addresses, one per line
+	Database       bool     // Whether to load lists from the database as well
+}
+
+// Default is the screener configured through the environment.
+var Default = New(config.ScreeningDenylistFiles, config.ScreeningAllowlistFiles, config.ScreeningDatabase)
+
+// New creates a screener for the given list files. Lists are loaded on Reload.
+func New(denylistFiles, allowlistFiles []string, database bool) *Screener {
+	return &Screener{
+		deny:           map[string]string{},
+		allow:          map[string]struct{}{},
+		protected:      map[string]struct{}{},
+		DenylistFiles:  denylistFiles,
+		AllowlistFiles: allowlistFiles,
+		Database:       database,
+	}
+}
+
+// Protect always denies payouts to the given addresses, such as our own hot wallet.
+func (s *Screener) Protect(addresses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range addresses {
+		if len(item) != 0 {
+			s.protected[Normalize(item)] = struct{}{}
+		}
+	}
+}
+
+// Check screens a destination address.
+func (s *Screener) Check(addr string) Result {
+	key := Normalize(addr)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.protected[key]; ok {
+		return Result{Outcome: OutcomeDenied, List: ListProtected}
+	}
+
+	if list, ok := s.deny[key]; ok {
+		return Result{Outcome: OutcomeDenied, List: list}
+	}
+
+	if len(s.allow) != 0 {
+		if _, ok := s.allow[key]; !ok {
+			return Result{Outcome: OutcomeDenied, List: ListAllowlist}
+		}
+	}
+
+	return Result{Outcome: OutcomePassed}
+}
+
+// Reload loads every list again and swaps them in at once.
+// On error the previously loaded lists stay in use.
+func (s *Screener) Reload(ctx context.Context) error {
+	deny := map[string]string{}
+	allow := map[string]struct{}{}
+
+	for _, file := range s.DenylistFiles {
+		if err := readFile(file, func(addr string) {
+			deny[addr] = listName(file)
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range s.AllowlistFiles {
+		if err := readFile(file, func(addr string) {
+			allow[addr] = struct{}{}
+		}); err != nil {
+			return err
+		}
+	}
+
+	if s.Database {
+		entries, err := storage.SCREENING_LIST_GET_CONTEXT(ctx)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			switch entry.Kind {
+			case KindDeny:
+				deny[Normalize(entry.Address)] = entry.List
+			case KindAllow:
+				allow[Normalize(entry.Address)] = struct{}{}
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.deny = deny
+	s.allow = allow
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Run reloads the lists every interval. Errors keep the previous lists and are passed to onError.
+func (s *Screener) Run(interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.Reload(context.Background()); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// Normalize converts a user friendly or raw address into its raw form, so that
+// bounceable, non-bounceable and raw spellings of one address match each other.
+// Unparsable values are only trimmed.
+func Normalize(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if parsed, err := address.ParseAddr(addr); err == nil {
+		return parsed.StringRaw()
+	}
+	if parsed, err := address.ParseRawAddr(addr); err == nil {
+		return parsed.StringRaw()
+	}
+	return addr
+}
+
+// Canonical converts a user friendly or raw address into the non-bounceable
+// mainnet form that payouts are stored, limited and sent under, so that every
+// spelling of one wallet shares its limits. Unparsable values are rejected.
+func Canonical(addr string) (string, error) {
+	addr = strings.TrimSpace(addr)
+	parsed, err := address.ParseAddr(addr)
+	if err != nil {
+		if parsed, err = address.ParseRawAddr(addr); err != nil {
+			return "", err
+		}
+	}
+	return parsed.Bounce(false).Testnet(false).String(), nil
+}
+
+// readFile calls fn with every normalized address of the file, skipping empty lines and # comments.
+func readFile(file string, fn func(addr string)) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if len(line) != 0 {
+			fn(Normalize(line))
+		}
+	}
+	return scanner.Err()
+}
+
+// listName derives the name of a list from its file name.
+func listName(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
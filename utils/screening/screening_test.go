@@ -0,0 +1,90 @@
+package screening
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	bounceable    = "EQCvxJy4eG8hyHBFsZ7eePxrRsUQSFE_jpptRAYBmcG_DOGS"
+	nonBounceable = "UQAfB7KjPFWxD5GpnvQ6s2yhMaxig7seoSe8URS_o3vCw-DI"
+	hotWallet     = "UQDUewtDjeb4WwiSutRkXXTcne5jxL1QiUJt1WEy12Zz2Qpu"
+)
+
+func writeList(t *testing.T, name, content string) string {
+	file := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
+
+func TestNormalize(t *testing.T) {
+	raw := Normalize(bounceable)
+	assert.Equal(t, raw, Normalize(raw))
+	assert.Equal(t, raw, Normalize("  "+bounceable+"  "))
+	assert.Equal(t, "not-an-address", Normalize(" not-an-address "))
+}
+
+func TestCanonical(t *testing.T) {
+	canonical, err := Canonical(bounceable)
+	assert.NoError(t, err)
+	assert.Equal(t, "UQCvxJy4eG8hyHBFsZ7eePxrRsUQSFE_jpptRAYBmcG_DLxX", canonical)
+
+	// Every spelling of the address ends up in the same form
+	for _, addr := range []string{canonical, Normalize(bounceable), "  " + bounceable + "  "} {
+		got, err := Canonical(addr)
+		assert.NoError(t, err)
+		assert.Equal(t, canonical, got)
+	}
+
+	_, err = Canonical("not-an-address")
+	assert.Error(t, err)
+}
+
+func TestScreener(t *testing.T) {
+	t.Run("Denylist", func(t *testing.T) {
+		s := New([]string{writeList(t, "exchanges.txt", "# exchange deposits\n"+bounceable+" # no memo\n\n")}, nil, false)
+		assert.NoError(t, s.Reload(context.Background()))
+
+		// The raw spelling of a listed address is denied as well
+		assert.Equal(t, Result{Outcome: OutcomeDenied, List: "exchanges"}, s.Check(Normalize(bounceable)))
+		assert.False(t, s.Check(nonBounceable).Denied())
+	})
+
+	t.Run("Allowlist", func(t *testing.T) {
+		s := New(nil, []string{writeList(t, "allow.txt", nonBounceable+"\n")}, false)
+		assert.NoError(t, s.Reload(context.Background()))
+
+		assert.False(t, s.Check(nonBounceable).Denied())
+		assert.Equal(t, Result{Outcome: OutcomeDenied, List: ListAllowlist}, s.Check(bounceable))
+	})
+
+	t.Run("Protected", func(t *testing.T) {
+		s := New(nil, nil, false)
+		s.Protect(hotWallet, "")
+
+		assert.Equal(t, Result{Outcome: OutcomeDenied, List: ListProtected}, s.Check(hotWallet))
+		assert.False(t, s.Check(bounceable).Denied())
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		file := writeList(t, "deny.txt", bounceable+"\n")
+		s := New([]string{file}, nil, false)
+		assert.NoError(t, s.Reload(context.Background()))
+		assert.True(t, s.Check(bounceable).Denied())
+
+		// Changes on disk are picked up by the next reload
+		assert.NoError(t, os.WriteFile(file, []byte(nonBounceable+"\n"), 0o600))
+		assert.NoError(t, s.Reload(context.Background()))
+		assert.False(t, s.Check(bounceable).Denied())
+		assert.True(t, s.Check(nonBounceable).Denied())
+
+		// A missing file keeps the previous lists
+		assert.NoError(t, os.Remove(file))
+		assert.Error(t, s.Reload(context.Background()))
+		assert.True(t, s.Check(nonBounceable).Denied())
+	})
+}
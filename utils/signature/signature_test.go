@@ -0,0 +1,79 @@
+package signature
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mint/utils/mysql"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	canonical := Canonical("post", "/withdraw", "1700000000", "abc", []byte(`{"amount":1}`))
+
+	// The method is upper-cased and the body is replaced by its SHA-256 hash
+	body := sha256.Sum256([]byte(`{"amount":1}`))
+	assert.Equal(t, "POST\n/withdraw\n1700000000\nabc\n"+hex.EncodeToString(body[:]), canonical)
+
+	signature := Sign("secret", canonical)
+	assert.True(t, Verify("secret", canonical, signature))
+	assert.False(t, Verify("other", canonical, signature))
+	assert.False(t, Verify("secret", canonical+"x", signature))
+	assert.False(t, Verify("secret", canonical, "not-hex"))
+}
+
+func TestSecret(t *testing.T) {
+	secret := Secret("master", "1f2e3d4c5b6a7980")
+	assert.Len(t, secret, 64)
+
+	// The secret is stable, and depends on both the master key and the key identifier
+	assert.Equal(t, secret, Secret("master", "1f2e3d4c5b6a7980"))
+	assert.NotEqual(t, secret, Secret("other", "1f2e3d4c5b6a7980"))
+	assert.NotEqual(t, secret, Secret("master", "0000000000000000"))
+}
+
+func TestCheckTimestamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	assert.NoError(t, CheckTimestamp("1700000000", now, time.Minute))
+	assert.NoError(t, CheckTimestamp("1699999950", now, time.Minute))
+	assert.ErrorIs(t, CheckTimestamp("1699999000", now, time.Minute), ErrExpired)
+	assert.ErrorIs(t, CheckTimestamp(strconv.Itoa(1700001000), now, time.Minute), ErrExpired)
+	assert.ErrorIs(t, CheckTimestamp("abc", now, time.Minute), ErrMalformed)
+}
+
+func TestNonceStore(t *testing.T) {
+	store := NewNonceStore(mysql.NewInMemoryStorage())
+
+	assert.NoError(t, store.Use("key", "nonce", time.Minute))
+	assert.ErrorIs(t, store.Use("key", "nonce", time.Minute), ErrReplayed)
+
+	// The same nonce of another key is independent
+	assert.NoError(t, store.Use("other", "nonce", time.Minute))
+}
+
+func TestNonceStoreConcurrent(t *testing.T) {
+	store := NewNonceStore(mysql.NewInMemoryStorage())
+
+	// A nonce is accepted once however many requests carry it at the same time
+	accepted := atomic.Int32{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.Use("key", "nonce", time.Minute) == nil {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), accepted.Load())
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys shared by the spans of the payout pipeline.
+const (
+	KeyTransaction = attribute.Key("mint.transaction") // Transaction identifier of a payout
+	KeyBatchID     = attribute.Key("mint.batch_id")    // Identifier of a batch sent by the worker
+	KeyBatchSize   = attribute.Key("mint.batch_size")  // Number of payouts in a batch
+	KeyExec        = attribute.Key("db.operation")     // Name of the stored procedure
+)
+
+// Tracer starts the spans of every package. It delegates to the provider installed
+// by Setup and produces no-op spans until then.
+var Tracer = otel.Tracer("mint")
+
+// propagator serializes span contexts in the W3C Trace Context format.
+var propagator = propagation.TraceContext{}
+
+// Setup installs the global tracer provider exporting spans to the OTLP/HTTP collector
+// at endpoint. When endpoint is empty tracing stays disabled. The returned function
+// flushes pending spans and must be called before the process exits.
+func Setup(ctx context.Context, endpoint, service string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+
+	if len(endpoint) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Inject returns the span context of ctx as a "traceparent" value that can be
+// stored alongside a payout, or an empty string when ctx carries no span.
+func Inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// InjectHeader adds the span context of ctx to outgoing request headers.
+func InjectHeader(ctx context.Context, header propagation.HeaderCarrier) {
+	propagator.Inject(ctx, header)
+}
+
+// Extract returns ctx carrying the remote span context from incoming request headers.
+func Extract(ctx context.Context, header propagation.HeaderCarrier) context.Context {
+	return propagator.Extract(ctx, header)
+}
+
+// Links converts stored "traceparent" values into span links, skipping empty or malformed ones.
+func Links(traceparents ...string) []trace.Link {
+	var links []trace.Link
+	for _, traceparent := range traceparents {
+		ctx := propagator.Extract(context.Background(), propagation.MapCarrier{"traceparent": traceparent})
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			links = append(links, trace.Link{SpanContext: span})
+		}
+	}
+	return links
+}
+
+// StartChild starts a span like Tracer.Start, but only as the child of a span carried by
+// ctx. Without one it returns ctx and a non-recording span, so that polls running every
+// tick, mostly on empty tables, do not each start a trace of their own.
+func StartChild(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return Tracer.Start(ctx, name, opts...)
+}
+
+// Fail records err on span and marks the span as failed. A nil err is ignored.
+func Fail(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
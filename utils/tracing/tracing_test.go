@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestInjectLinks(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "intake")
+	defer span.End()
+
+	traceparent := Inject(ctx)
+	assert.NotEmpty(t, traceparent)
+
+	links := Links(traceparent, "", "malformed")
+	assert.Len(t, links, 1)
+	assert.Equal(t, span.SpanContext().TraceID(), links[0].SpanContext.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), links[0].SpanContext.SpanID())
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	assert.Empty(t, Inject(context.Background()))
+	assert.Empty(t, Links(Inject(context.Background())))
+}
+
+func TestHeaders(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "callback")
+	defer span.End()
+
+	header := http.Header{}
+	InjectHeader(ctx, propagation.HeaderCarrier(header))
+	assert.NotEmpty(t, header.Get("traceparent"))
+
+	remote := trace.SpanContextFromContext(Extract(context.Background(), propagation.HeaderCarrier(header)))
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.True(t, remote.IsRemote())
+}
+
+func TestFail(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, span := provider.Tracer("test").Start(context.Background(), "ok")
+	Fail(span, nil)
+	span.End()
+
+	_, span = provider.Tracer("test").Start(context.Background(), "failed")
+	Fail(span, errors.New("boom"))
+	span.End()
+
+	spans := recorder.Ended()
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+}
+
+func TestStartChild(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	// Without a parent no span is recorded
+	ctx, span := StartChild(context.Background(), "poll")
+	assert.False(t, span.IsRecording())
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+	span.End()
+	assert.Empty(t, recorder.Ended())
+
+	parent, root := provider.Tracer("test").Start(context.Background(), "batch")
+	_, span = StartChild(parent, "query")
+	span.End()
+	root.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "query", spans[0].Name())
+	assert.Equal(t, root.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "", "mint")
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
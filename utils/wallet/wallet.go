@@ -3,12 +3,19 @@ package wallet
 import (
 	"context"
 	"encoding/base64"
+	"math/big"
+	"mint/utils/logger"
 	"mint/utils/tonlib"
+	"mint/utils/tracing"
 	// "time"
 
+	"github.com/xssnick/tonutils-go/address"
 	"github.com/xssnick/tonutils-go/liteclient"
 	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/ton/jetton"
 	"github.com/xssnick/tonutils-go/ton/wallet"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var Core *Wallet
@@ -22,9 +29,10 @@ type Wallet struct {
 }
 
 type Transaction struct {
-	Wallet  string `json:"wallet" binding:"required"`      // The recipient wallet address
-	Amount  uint64 `json:"amount" binding:"required,gt=0"` // The amount of tokens to withdraw; must be greater than zero
-	Message string `json:"message" binding:"required"`     // An optional message or comment for the transaction
+	Transaction string `json:"transaction"`                                            // The transaction identifier of the payout, used for logging
+	Wallet      string `json:"wallet" binding:"required"`                              // The recipient wallet address
+	Amount      uint64 `json:"amount" binding:"required,gt=0,lte=9223372036854775807"` // The amount of tokens to withdraw; must be greater than zero and fit in an int64
+	Message     string `json:"message" binding:"required"`                             // An optional message or comment for the transaction
 }
 
 // New initializes and returns a new Wallet object using the provided seed words and network configuration URL.
@@ -81,7 +89,13 @@ func New(words []string, network string) (*Wallet, error) {
 // It returns the balance as a uint64 or an error if retrieval fails.
 func (w *Wallet) Balance() (uint64, error) {
 
-	balance, err := w.GetBalance(w.Context, w.Block)
+	// Use the latest block, the one loaded at startup only reflects the balance at that time
+	block, err := w.Api.CurrentMasterchainInfo(w.Context)
+	if err != nil {
+		return 0, err
+	}
+
+	balance, err := w.GetBalance(w.Context, block)
 	if err != nil {
 		return 0, err
 	}
@@ -89,10 +103,58 @@ func (w *Wallet) Balance() (uint64, error) {
 	return balance.Nano().Uint64(), nil
 }
 
+// Seqno retrieves and returns the current sequence number of the wallet contract.
+// It returns the seqno or an error if the get method cannot be executed.
+func (w *Wallet) Seqno() (uint64, error) {
+
+	block, err := w.Api.CurrentMasterchainInfo(w.Context)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := w.Api.RunGetMethod(w.Context, block, w.WalletAddress(), "seqno")
+	if err != nil {
+		return 0, err
+	}
+
+	seqno, err := result.Int(0)
+	if err != nil {
+		return 0, err
+	}
+
+	return seqno.Uint64(), nil
+}
+
+// Ping checks that a liteserver answers within the lifetime of ctx.
+func (w *Wallet) Ping(ctx context.Context) error {
+	_, err := w.Api.CurrentMasterchainInfo(ctx)
+	return err
+}
+
+// JettonBalance retrieves and returns the current balance of the given jetton held by the wallet.
+// It returns the balance in the smallest units of the jetton or an error if retrieval fails.
+func (w *Wallet) JettonBalance(jettonAddress string) (*big.Int, error) {
+
+	master, err := address.ParseAddr(jettonAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the jetton wallet owned by this wallet
+	jettonWallet, err := jetton.NewJettonMasterClient(w.Api, master).GetJettonWallet(w.Context, w.WalletAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	return jettonWallet.GetBalance(w.Context)
+}
+
 // Withdraw creates and executes a transaction to transfer Jettons from one address to another.
 // Requires jetton details, from and to addresses, amount, and a message.
 // Returns the transaction hash as a base64 encoded string or an error if the transaction fails.
+// The logger and the span carried by ctx are used for every log line and child span.
 func (w *Wallet) Withdraw(
+	ctx context.Context,
 	jetton string,
 	fromAddress string,
 	transactions []Transaction,
@@ -101,8 +163,23 @@ func (w *Wallet) Withdraw(
 	// message string,
 ) (string, error) {
 
+	ctx, span := tracing.Tracer.Start(ctx, "wallet.withdraw",
+		trace.WithAttributes(tracing.KeyBatchSize.Int(len(transactions))),
+	)
+	defer span.End()
+
+	log := logger.FromContext(ctx)
+
+	// Resolve the jetton wallet once, the transfers below reuse the cached address
+	if err := resolveJettonWallet(ctx, fromAddress, jetton); err != nil {
+		log.Error("failed to resolve jetton wallet", "error", err)
+		tracing.Fail(span, err)
+		return "", err
+	}
+
 	var messages []*wallet.Message
 	for _, item := range transactions {
+		log.Debug("building transfer", logger.KeyTransaction, item.Transaction, "wallet", item.Wallet, "amount", item.Amount)
 
 		// Create a transaction message with specific transfer options
 		msg, err := tonlib.CreateTransaction(tonlib.JettonTransferOption{
@@ -114,6 +191,8 @@ func (w *Wallet) Withdraw(
 		})
 
 		if err != nil {
+			log.Error("failed to build transfer", logger.KeyTransaction, item.Transaction, "error", err)
+			tracing.Fail(span, err)
 			return "", err
 		}
 
@@ -125,13 +204,34 @@ func (w *Wallet) Withdraw(
 	}
 
 	// Send the transaction and wait for confirmation
-	tx, _, err := w.SendManyWaitTransaction(context.Background(), messages)
+	tx, _, err := w.SendManyWaitTransaction(ctx, messages)
 	if err != nil {
+		log.Error("failed to send transfers", "count", len(messages), "error", err)
+		tracing.Fail(span, err)
 		return "", err
 	}
 
 	// Return the transaction hash as a base64 encoded string
-	return base64.StdEncoding.EncodeToString(tx.Hash), nil
+	hash := base64.StdEncoding.EncodeToString(tx.Hash)
+	log.Info("transfers confirmed", "count", len(messages), "hash", hash)
+	span.SetAttributes(attribute.String("ton.tx_hash", hash))
+	return hash, nil
+}
+
+// resolveJettonWallet looks up the jetton wallet of owner in its own span.
+func resolveJettonWallet(ctx context.Context, owner, jetton string) error {
+	_, span := tracing.Tracer.Start(ctx, "jetton.resolve",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("ton.owner", owner),
+			attribute.String("ton.jetton", jetton),
+		),
+	)
+	defer span.End()
+
+	_, err := tonlib.GetJettonWallet(owner, jetton)
+	tracing.Fail(span, err)
+	return err
 }
 
 // Withdraw creates and executes a transaction to transfer Jettons from one address to another.
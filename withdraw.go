@@ -1,10 +1,16 @@
 package main
 
 import (
-	// "mint/config"
-	// "mint/config"
+	"mint/config"
+	"mint/shared/middleware"
+	"mint/shared/models"
 	"mint/storage"
+	"mint/utils/approval"
+	"mint/utils/limits"
+	"mint/utils/logger"
 	"mint/utils/msg"
+	"mint/utils/screening"
+	"mint/utils/tracing"
 	// "mint/utils/wallet"
 
 	"github.com/gin-gonic/gin"
@@ -14,9 +20,9 @@ import (
 // It includes fields for the recipient's wallet address, the amount to transfer, and an optional message.
 type WithdrawBody struct {
 	Transaction string `json:"transaction" bindung:"transaction"`
-	Wallet      string `json:"wallet" binding:"required"`      // The recipient wallet address
-	Amount      uint64 `json:"amount" binding:"required,gt=0"` // The amount of tokens to withdraw; must be greater than zero
-	Message     string `json:"message" binding:"required"`     // An optional message or comment for the transaction
+	Wallet      string `json:"wallet" binding:"required"`                              // The recipient wallet address
+	Amount      uint64 `json:"amount" binding:"required,gt=0,lte=9223372036854775807"` // The amount of tokens to withdraw; must be greater than zero and fit in an int64
+	Message     string `json:"message" binding:"required"`                             // An optional message or comment for the transaction
 	// Items       []wallet.Transaction `json:"items" binding:"required"`
 }
 
@@ -32,23 +38,73 @@ func handlerWithdraw(ctx *gin.Context) {
 
 	// Bind the incoming JSON to WithdrawBody and validate the input according to the struct tags
 	if err := ctx.ShouldBindJSON(&body); err != nil {
-		msg.InvalidFields(ctx) // Respond with an error message if validation fails
+		msg.Invalid(ctx, err) // Respond with an error message if validation fails
 		return
 	}
 
-	result, err := storage.QUEUE_ADD(
+	// Store, limit and send the destination in one form, whatever spelling the caller used
+	wallet, err := screening.Canonical(body.Wallet)
+	if err != nil {
+		msg.InvalidFields(ctx, msg.FieldError{Field: "wallet", Rule: "address"})
+		return
+	}
+	body.Wallet = wallet
+
+	// Screen the destination before anything is queued and keep the outcome for the record
+	screen := screening.Default.Check(body.Wallet)
+	if _, err := storage.SCREENING_ADD_CONTEXT(ctx.Request.Context(), body.Transaction, body.Wallet, screen.Outcome, screen.List); err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+	if screen.Denied() {
+		msg.Screened(ctx)
+		return
+	}
+
+	// Payouts over a limit are queued for manual approval instead of being rejected
+	totals, err := limits.Default.Load(ctx.Request.Context(), config.WalletJetton, body.Wallet, false)
+	if err != nil {
+		msg.Failure(ctx, err)
+		return
+	}
+
+	status := models.StatusPending
+	reason := limits.Default.Evaluate(int64(body.Amount), totals)
+	if len(reason) == 0 && approval.Required(int64(body.Amount)) {
+		reason = approval.ReasonThreshold
+	}
+	if len(reason) != 0 {
+		status = models.StatusApproval
+	}
+
+	result, err := storage.QUEUE_ADD_CONTEXT(
+		ctx.Request.Context(),
 		body.Transaction,
 		body.Wallet,
 		int64(body.Amount),
 		body.Message,
+		config.WalletJetton,
+		middleware.Actor(ctx),
+		status,
+		reason,
+		tracing.Inject(ctx.Request.Context()),
 	)
 
 	if err != nil {
-		msg.BadRequest(ctx, err.Error())
+		msg.Failure(ctx, err)
+		return
 	}
 
+	logger.FromContext(ctx.Request.Context()).Info("payout queued",
+		logger.KeyTransaction, body.Transaction,
+		"id", result.LastInsertID,
+		"status", status,
+		"reason", reason,
+	)
+
 	msg.Send(ctx, map[string]any{
-		"result": result,
+		"result": true,
+		"status": status,
 	})
 
 	// // Perform the withdrawal operation using the wallet service, passing the jetton, source, destination, amount, and message